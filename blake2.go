@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_noblake2
+
+package hash
+
+import (
+	"crypto"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+)
+
+const (
+	// string IDs for the hash functions.
+	blake2xb = "BLAKE2XB"
+	blake2xs = "BLAKE2XS"
+)
+
+func newBlake2b256() hash.Hash {
+	h, _ := blake2b.New256(nil)
+	return h
+}
+
+func newBlake2b384() hash.Hash {
+	h, _ := blake2b.New384(nil)
+	return h
+}
+
+func newBlake2b512() hash.Hash {
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+func newBlake2s256() hash.Hash {
+	h, _ := blake2s.New256(nil)
+	return h
+}
+
+func newBlake2xb() XOF {
+	x, _ := blake2b.NewXOF(blake2b.OutputLengthUnknown, nil)
+	return x
+}
+
+func newBlake2xs() XOF {
+	x, _ := blake2s.NewXOF(blake2s.OutputLengthUnknown, nil)
+	return x
+}
+
+func cloneBlake2(state any) (any, bool) {
+	switch s := state.(type) {
+	case blake2b.XOF:
+		return s.Clone(), true
+	case blake2s.XOF:
+		return s.Clone(), true
+	default:
+		return nil, false
+	}
+}
+
+// The BLAKE2 functions are registered at package variable initialization, see registerBuiltin.
+var _ = registerBuiltin(registerBlake2)
+
+// registerBlake2 registers the BLAKE2 functions.
+func registerBlake2() {
+	register(BLAKE2B_256, newBlake2b256, crypto.BLAKE2b_256.String(), blake2b.BlockSize, blake2b.Size256, sec128)
+	register(BLAKE2B_384, newBlake2b384, crypto.BLAKE2b_384.String(), blake2b.BlockSize, blake2b.Size384, sec192)
+	register(BLAKE2B_512, newBlake2b512, crypto.BLAKE2b_512.String(), blake2b.BlockSize, blake2b.Size, sec256)
+	register(BLAKE2S_256, newBlake2s256, crypto.BLAKE2s_256.String(), blake2s.BlockSize, blake2s.Size, sec128)
+	register(BLAKE2XB, newBlake2xb, blake2xb, 0, size256, sec128)
+	register(BLAKE2XS, newBlake2xs, blake2xs, 0, size256, sec128)
+
+	keyedHashes[BLAKE2B_256] = newKeyedFixed(BLAKE2B_256, blake2b.New256)
+	keyedHashes[BLAKE2B_384] = newKeyedFixed(BLAKE2B_384, blake2b.New384)
+	keyedHashes[BLAKE2B_512] = newKeyedFixed(BLAKE2B_512, blake2b.New512)
+	keyedHashes[BLAKE2S_256] = newKeyedFixed(BLAKE2S_256, blake2s.New256)
+
+	stateCloners = append(stateCloners, cloneBlake2)
+}
@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_noblake3
+
+package hash
+
+import (
+	"github.com/bytemare/hash/internal/blake3"
+)
+
+// blake3Name is the string ID of BLAKE3.
+const blake3Name = "BLAKE3"
+
+func newBlake3() XOF {
+	return blake3.New()
+}
+
+func newKeyedBlake3(key []byte) (XOF, error) {
+	h, err := blake3.NewKeyed(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// NewBLAKE3DeriveKey returns a BLAKE3 Hasher in the derive_key mode for the given context string. The key material
+// is written to it, and the derived key is then read from it. The context string should be hardcoded, globally unique,
+// and application-specific. Resetting the Hasher keeps the context.
+func NewBLAKE3DeriveKey(context string) *ExtendableHash {
+	return &ExtendableHash{
+		xof: blake3.NewDeriveKey(context),
+		id:  BLAKE3,
+	}
+}
+
+func cloneBlake3(state any) (any, bool) {
+	s, ok := state.(*blake3.Hasher)
+	if !ok {
+		return nil, false
+	}
+
+	return s.Clone(), true
+}
+
+// The BLAKE3 functions are registered at package variable initialization, see registerBuiltin.
+var _ = registerBuiltin(registerBlake3)
+
+// registerBlake3 registers the BLAKE3 functions.
+func registerBlake3() {
+	register(BLAKE3, newBlake3, blake3Name, blake3.BlockSize, blake3.Size, sec128)
+
+	keyedHashes[BLAKE3] = newKeyedXOF(BLAKE3, newKeyedBlake3)
+
+	stateCloners = append(stateCloners, cloneBlake3)
+}
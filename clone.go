@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"encoding"
+	"errors"
+)
+
+// ErrNotClonable indicates that the state of the hash function can't be copied, e.g. because it is keyed and the
+// underlying implementation does not support it.
+var ErrNotClonable = errors.New("hash state can't be cloned")
+
+// stateCloners are the native copy methods of the underlying implementations of the built-in hash functions. Each
+// returns a copy of state, or false if it does not support state's type. They are added by the files of the hash
+// function families, such that excluding a family with a build tag doesn't link its implementation.
+var stateCloners []func(state any) (any, bool)
+
+// cloneState returns an independent copy of state, the underlying implementation of a hash function. If the
+// implementation has no native copy method, the state is serialized and restored into an instance returned by fresh.
+// It returns false if the state can't be copied.
+func cloneState[T any](state T, fresh func() T) (T, bool) {
+	for _, cloner := range stateCloners {
+		if c, ok := cloner(state); ok {
+			if t, ok := c.(T); ok {
+				return t, true
+			}
+
+			return state, false
+		}
+	}
+
+	var c any
+
+	switch s := any(state).(type) {
+	case encoding.BinaryMarshaler:
+		serialized, err := s.MarshalBinary()
+		if err != nil {
+			return state, false
+		}
+
+		c = fresh()
+
+		u, ok := c.(encoding.BinaryUnmarshaler)
+		if !ok || u.UnmarshalBinary(serialized) != nil {
+			return state, false
+		}
+	default:
+		return state, false
+	}
+
+	t, ok := c.(T)
+	if !ok {
+		return state, false
+	}
+
+	return t, true
+}
+
+// Clone returns an independent copy of the Hasher in its current state. It panics with ErrNotClonable if the
+// underlying state can't be copied, e.g. for keyed BLAKE2.
+func (h *Fixed) Clone() Hasher {
+	c, err := h.CloneE()
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// CloneE returns an independent copy of the Hasher in its current state, or ErrNotClonable if the underlying state
+// can't be copied, e.g. for keyed BLAKE2.
+func (h *Fixed) CloneE() (*Fixed, error) {
+	c, ok := cloneState(h.hash, h.f)
+	if !ok {
+		return nil, ErrNotClonable
+	}
+
+	return &Fixed{
+		hash:     c,
+		f:        h.f,
+		id:       h.id,
+		standard: h.standard,
+	}, nil
+}
+
+// Clone returns an independent copy of the Hasher in its current state, including the output already read. It panics
+// with ErrNotClonable if the underlying state can't be copied.
+func (h *ExtendableHash) Clone() Hasher {
+	c, err := h.CloneE()
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// CloneE returns an independent copy of the Hasher in its current state, including the output already read, or
+// ErrNotClonable if the underlying state can't be copied.
+func (h *ExtendableHash) CloneE() (*ExtendableHash, error) {
+	c, ok := cloneState(h.xof, func() XOF {
+		return h.id.GetXOF().xof
+	})
+	if !ok {
+		return nil, ErrNotClonable
+	}
+
+	return &ExtendableHash{
+		xof:      c,
+		id:       h.id,
+		standard: h.standard,
+	}, nil
+}
@@ -0,0 +1,369 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+// Command hashsum computes and checks message digests with the hash functions of github.com/bytemare/hash. Its
+// output and checksum files are compatible with GNU coreutils' sha256sum and with BSD-style tagged checksums.
+//
+// Usage:
+//
+//	hashsum [-a algorithm] [--tag] [--length bits] [FILE]...
+//	hashsum [-a algorithm] --check [--quiet] [--status] [--strict] [FILE]...
+//
+// The algorithm is selected by name, e.g. sha256, sha3-256, blake2b-512, shake128, or blake2xb, and defaults to
+// SHA-256. The output length of extendable output functions can be set in bits with --length. With no FILE, or when
+// FILE is -, standard input is read. Flags must precede files.
+//
+// The exit code is 0 on success, 1 if a file could not be read or a checksum did not match, and 2 on usage errors.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+
+	stdinName = "-"
+)
+
+var (
+	errLength       = errors.New("--length must be a positive multiple of 8 and is only supported for XOFs")
+	errCheckOptions = errors.New("--quiet, --status, and --strict are only meaningful when verifying checksums")
+	errTagCheck     = errors.New("--tag and --length can't be used when verifying checksums")
+)
+
+type config struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	algorithm      hash.Hash
+	length         int
+	tag            bool
+	check          bool
+	quiet          bool
+	status         bool
+	strict         bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command with the given arguments, and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &config{
+		stdin:     stdin,
+		stdout:    stdout,
+		stderr:    stderr,
+		algorithm: hash.SHA256,
+	}
+
+	files, err := c.parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "hashsum: %v\n", err)
+		return exitUsage
+	}
+
+	if len(files) == 0 {
+		files = []string{stdinName}
+	}
+
+	if c.check {
+		return c.checkFiles(files)
+	}
+
+	return c.hashFiles(files)
+}
+
+func (c *config) parse(args []string) ([]string, error) {
+	var bits int
+
+	fs := flag.NewFlagSet("hashsum", flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: hashsum [-a algorithm] [--tag] [--length bits] [FILE]...")
+		fmt.Fprintln(fs.Output(), "       hashsum [-a algorithm] --check [--quiet] [--status] [--strict] [FILE]...")
+		fs.PrintDefaults()
+	}
+
+	fs.Var(&c.algorithm, "a", "hash function `name`, e.g. sha256, sha3-256, blake2b-512, shake128 (default SHA-256)")
+	fs.Var(&c.algorithm, "algorithm", "same as -a")
+	fs.IntVar(&bits, "length", 0, "output length in `bits` of extendable output functions")
+	fs.IntVar(&bits, "l", 0, "same as --length")
+	fs.BoolVar(&c.tag, "tag", false, "create BSD-style checksums")
+	fs.BoolVar(&c.check, "check", false, "read checksums from the FILEs and verify them")
+	fs.BoolVar(&c.check, "c", false, "same as --check")
+	fs.BoolVar(&c.quiet, "quiet", false, "don't print OK for each successfully verified file")
+	fs.BoolVar(&c.status, "status", false, "don't output anything, the exit code shows success")
+	fs.BoolVar(&c.strict, "strict", false, "exit non-zero for improperly formatted checksum lines")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case bits < 0 || bits%8 != 0 || (bits != 0 && c.algorithm.Type() != hash.ExtendableOutputFunction):
+		return nil, errLength
+	case bits/8 != 0 && bits/8 < c.algorithm.Size():
+		return nil, fmt.Errorf("--length must be at least %d bits for %s", 8*c.algorithm.Size(), c.algorithm)
+	case c.check && (c.tag || bits != 0):
+		return nil, errTagCheck
+	case !c.check && (c.quiet || c.status || c.strict):
+		return nil, errCheckOptions
+	}
+
+	c.length = bits / 8
+	if c.length == 0 {
+		c.length = c.algorithm.Size()
+	}
+
+	return fs.Args(), nil
+}
+
+func (c *config) open(name string) (io.ReadCloser, error) {
+	if name == stdinName {
+		return io.NopCloser(c.stdin), nil
+	}
+
+	return os.Open(name)
+}
+
+// digest returns the length bytes digest of the file with the hash function h.
+func (c *config) digest(h hash.Hash, length int, name string) ([]byte, error) {
+	f, err := c.open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	digest, _, err := h.HashReader(f, uint(length))
+
+	return digest, err
+}
+
+// tagName returns the name of the hash function in BSD-style checksums, which drops the hyphen of the SHA-2 names
+// as coreutils does, e.g. SHA256.
+func tagName(h hash.Hash) string {
+	if name, ok := strings.CutPrefix(h.String(), "SHA-"); ok {
+		return "SHA" + name
+	}
+
+	return h.String()
+}
+
+// escape escapes backslashes and newlines in file names as coreutils does, and reports whether it did.
+func escape(name string) (string, bool) {
+	if !strings.ContainsAny(name, "\\\n") {
+		return name, false
+	}
+
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(name), true
+}
+
+func (c *config) hashFiles(files []string) int {
+	code := exitOK
+
+	for _, name := range files {
+		digest, err := c.digest(c.algorithm, c.length, name)
+		if err != nil {
+			fmt.Fprintf(c.stderr, "hashsum: %s: %v\n", name, err)
+			code = exitFailure
+
+			continue
+		}
+
+		escapedName, escaped := escape(name)
+		if escaped {
+			fmt.Fprint(c.stdout, "\\")
+		}
+
+		if c.tag {
+			fmt.Fprintf(c.stdout, "%s (%s) = %x\n", tagName(c.algorithm), escapedName, digest)
+		} else {
+			fmt.Fprintf(c.stdout, "%x  %s\n", digest, escapedName)
+		}
+	}
+
+	return code
+}
+
+// checkLine is a parsed line of a checksum file.
+type checkLine struct {
+	name     string
+	expected []byte
+	hash     hash.Hash
+}
+
+// parseBSD parses the BSD-style checksum line "ALGORITHM (name) = digest".
+func parseBSD(line string) (h hash.Hash, name, digest string, ok bool) {
+	algorithm, rest, found := strings.Cut(line, " (")
+	i := strings.LastIndex(rest, ") = ")
+
+	if !found || i < 0 {
+		return 0, "", "", false
+	}
+
+	h, err := hash.Parse(algorithm)
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	return h, rest[:i], rest[i+len(") = "):], true
+}
+
+// parseGNU parses the GNU-style checksum line "digest  name", or "digest *name" for binary mode.
+func parseGNU(line string) (name, digest string, ok bool) {
+	digest, name, found := strings.Cut(line, " ")
+	if !found || name == "" || (name[0] != ' ' && name[0] != '*') {
+		return "", "", false
+	}
+
+	return name[1:], digest, true
+}
+
+// parseLine parses a BSD-style or GNU-style checksum line, the latter using the hash function h.
+func parseLine(line string, h hash.Hash) (*checkLine, bool) {
+	line, escaped := strings.CutPrefix(line, "\\")
+
+	bsdHash, name, digest, ok := parseBSD(line)
+	if ok {
+		h = bsdHash
+	} else if name, digest, ok = parseGNU(line); !ok {
+		return nil, false
+	}
+
+	if escaped {
+		name = strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(name)
+	}
+
+	expected, err := hex.DecodeString(digest)
+	if err != nil || name == "" || len(expected) < h.Size() ||
+		(h.Type() == hash.FixedOutputLength && len(expected) != h.Size()) {
+		return nil, false
+	}
+
+	return &checkLine{name: name, expected: expected, hash: h}, true
+}
+
+// checkResult counts the outcomes of checking checksum files.
+type checkResult struct {
+	mismatched, unreadable, improper int
+	failed                           bool
+}
+
+func (c *config) checkFiles(files []string) int {
+	var r checkResult
+
+	for _, name := range files {
+		c.checkFile(name, &r)
+	}
+
+	if !c.status {
+		warn := func(n int, singular, plural string) {
+			if n == 1 {
+				fmt.Fprintf(c.stderr, "hashsum: WARNING: 1 %s\n", singular)
+			} else if n > 1 {
+				fmt.Fprintf(c.stderr, "hashsum: WARNING: %d %s\n", n, plural)
+			}
+		}
+
+		warn(r.improper, "line is improperly formatted", "lines are improperly formatted")
+		warn(r.unreadable, "listed file could not be read", "listed files could not be read")
+		warn(r.mismatched, "computed checksum did NOT match", "computed checksums did NOT match")
+	}
+
+	if r.failed || r.mismatched != 0 || r.unreadable != 0 || (c.strict && r.improper != 0) {
+		return exitFailure
+	}
+
+	return exitOK
+}
+
+func (c *config) checkFile(checksums string, r *checkResult) {
+	f, err := c.open(checksums)
+	if err != nil {
+		fmt.Fprintf(c.stderr, "hashsum: %s: %v\n", checksums, err)
+		r.failed = true
+
+		return
+	}
+
+	defer f.Close()
+
+	valid := 0
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		line, ok := parseLine(text, c.algorithm)
+		if !ok {
+			r.improper++
+			continue
+		}
+
+		valid++
+
+		c.checkEntry(line, r)
+	}
+
+	if err = scanner.Err(); err != nil {
+		fmt.Fprintf(c.stderr, "hashsum: %s: %v\n", checksums, err)
+		r.failed = true
+
+		return
+	}
+
+	if valid == 0 {
+		fmt.Fprintf(c.stderr, "hashsum: %s: no properly formatted checksum lines found\n", checksums)
+		r.failed = true
+	}
+}
+
+func (c *config) checkEntry(line *checkLine, r *checkResult) {
+	digest, err := c.digest(line.hash, len(line.expected), line.name)
+
+	switch {
+	case err != nil:
+		r.unreadable++
+
+		if !c.status {
+			fmt.Fprintf(c.stderr, "hashsum: %s: %v\n", line.name, err)
+			fmt.Fprintf(c.stdout, "%s: FAILED open or read\n", line.name)
+		}
+	case !bytes.Equal(digest, line.expected):
+		r.mismatched++
+
+		if !c.status {
+			fmt.Fprintf(c.stdout, "%s: FAILED\n", line.name)
+		}
+	default:
+		if !c.status && !c.quiet {
+			fmt.Fprintf(c.stdout, "%s: OK\n", line.name)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func runTest(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+
+	return code, stdout.String(), stderr.String()
+}
+
+// skipUnavailable skips the test if one of the hash functions was excluded from the build with a build tag.
+func skipUnavailable(t *testing.T, hashes ...hash.Hash) {
+	t.Helper()
+
+	for _, h := range hashes {
+		if !h.Available() {
+			t.Skipf("hash function %d is excluded from the build", h)
+		}
+	}
+}
+
+func TestHashsum(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHAKE256)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+
+	if err := os.WriteFile(file, []byte("hello\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Expected outputs from coreutils' sha256sum, and python's hashlib for SHAKE256.
+	for _, test := range []struct {
+		stdout string
+		args   []string
+	}{
+		{"5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  " + file + "\n", []string{file}},
+		{
+			"SHA256 (" + file + ") = 5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03\n",
+			[]string{"--tag", file},
+		},
+		{
+			"c14c452e7339f46db763353b4a85b4c688fb2096ffabdc2a757e9001b171b7e791beb75d346c19c9e52995e33b3f7166a238ab" +
+				"9057dbc814a0fe4262e7cda426  -\n",
+			[]string{"-a", "shake256", "--length", "512"},
+		},
+	} {
+		code, stdout, stderr := runTest(t, "hello\n", test.args...)
+		if code != exitOK || stdout != test.stdout || stderr != "" {
+			t.Fatalf("%v: unexpected result %d %q %q", test.args, code, stdout, stderr)
+		}
+	}
+}
+
+func TestHashsumCheck(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHAKE128, hash.BLAKE2XB)
+
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.txt")
+	escaped := filepath.Join(dir, "b\\c.txt")
+
+	for _, name := range []string{file, escaped} {
+		if err := os.WriteFile(name, []byte("hello\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var checksums []string
+
+	for i, args := range [][]string{{"-a", "blake2xb", "--tag"}, {"-a", "shake128", "-l", "384"}} {
+		code, stdout, _ := runTest(t, "", append(args, file, escaped)...)
+		if code != exitOK {
+			t.Fatalf("%v: unexpected exit code %d", args, code)
+		}
+
+		name := filepath.Join(dir, fmt.Sprintf("sums%d", i))
+		if err := os.WriteFile(name, []byte(stdout+"garbage\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		checksums = append(checksums, name)
+	}
+
+	// GNU-style lines use the algorithm given on the command line, and BSD-style lines their own.
+	check := append([]string{"-c", "-a", "shake128"}, checksums...)
+
+	code, stdout, stderr := runTest(t, "", check...)
+	if code != exitOK || strings.Count(stdout, ": OK\n") != 4 || !strings.Contains(stderr, "2 lines are") {
+		t.Fatalf("unexpected result %d %q %q", code, stdout, stderr)
+	}
+
+	code, stdout, _ = runTest(t, "", append([]string{"--quiet"}, check...)...)
+	if code != exitOK || stdout != "" {
+		t.Fatalf("unexpected result %d %q", code, stdout)
+	}
+
+	if code, _, _ = runTest(t, "", append([]string{"--strict"}, check...)...); code != exitFailure {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+
+	if err := os.WriteFile(file, []byte("modified\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr = runTest(t, "", check...)
+	if code != exitFailure || strings.Count(stdout, ": FAILED\n") != 2 || !strings.Contains(stderr, "2 computed") {
+		t.Fatalf("unexpected result %d %q %q", code, stdout, stderr)
+	}
+
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+
+	code, stdout, stderr = runTest(t, "", append([]string{"--status"}, check...)...)
+	if code != exitFailure || stdout != "" || stderr != "" {
+		t.Fatalf("unexpected result %d %q %q", code, stdout, stderr)
+	}
+
+	// A GNU-style line from stdin, with the default algorithm.
+	line := "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03  " + escaped + "\n"
+	if code, stdout, _ = runTest(t, "\\"+strings.ReplaceAll(line, "\\", "\\\\"), "-c"); code != exitOK {
+		t.Fatalf("unexpected result %d %q", code, stdout)
+	}
+
+	if code, _, _ = runTest(t, "garbage\n", "-c"); code != exitFailure {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+}
+
+func TestHashsumUsage(t *testing.T) {
+	for _, args := range [][]string{
+		{"-a", "unknown"},
+		{"--length", "256"},
+		{"-a", "shake128", "--length", "12"},
+		{"-a", "shake128", "--length", "128"},
+		{"-c", "--tag"},
+		{"--quiet"},
+		{"--unknown"},
+	} {
+		if code, _, _ := runTest(t, "", args...); code != exitUsage {
+			t.Errorf("%v: unexpected exit code %d", args, code)
+		}
+	}
+
+	if code, _, _ := runTest(t, "", "-h"); code != exitOK {
+		t.Errorf("unexpected exit code %d", code)
+	}
+
+	if code, _, _ := runTest(t, "", filepath.Join(t.TempDir(), "missing")); code != exitFailure {
+		t.Errorf("unexpected exit code %d", code)
+	}
+}
@@ -0,0 +1,133 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrUnknownName indicates that a name does not identify an available hash function.
+var ErrUnknownName = errors.New("unknown hash function name")
+
+// aliases maps normalized alternative names to the built-in hash functions they designate, when normalizing the
+// canonical name is not enough.
+var aliases = map[string]Hash{
+	"SHA2224":    SHA224,
+	"SHA2256":    SHA256,
+	"SHA2384":    SHA384,
+	"SHA2512":    SHA512,
+	"SHA2512224": SHA512_224,
+	"SHA2512256": SHA512_256,
+	"BLAKE2B":    BLAKE2B_512,
+	"BLAKE2S":    BLAKE2S_256,
+}
+
+// normalizeName returns the upper case name without separators, such that e.g. "sha3_256" and "SHA3-256" match.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '/', ' ', '.':
+			return -1
+		default:
+			return r
+		}
+	}, strings.ToUpper(strings.TrimSpace(name)))
+}
+
+// Parse returns the available hash function identified by name, which is matched case-insensitively against the
+// canonical names returned by String, ignoring separators like '-', '_', and '/', and accepting common aliases like
+// "sha2-256". It returns ErrUnknownName if no available function matches.
+func Parse(name string) (Hash, error) {
+	n := normalizeName(name)
+	if n == "" {
+		return 0, ErrUnknownName
+	}
+
+	if h, ok := aliases[n]; ok && h.Available() {
+		return h, nil
+	}
+
+	for id := 0; id < maxRegistry; id++ {
+		if registeredHashes[id] && normalizeName(names[id]) == n {
+			return Hash(id), nil
+		}
+	}
+
+	return 0, ErrUnknownName
+}
+
+// MarshalText implements encoding.TextMarshaler, and returns the canonical name of the hash function, or an empty text
+// for the zero Hash. It returns ErrUnavailable if the function is not available.
+func (h Hash) MarshalText() ([]byte, error) {
+	if h == 0 {
+		return []byte{}, nil
+	}
+
+	if !h.Available() {
+		return nil, ErrUnavailable
+	}
+
+	return []byte(h.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, and sets h to the hash function identified by the text, as
+// accepted by Parse, or to the zero Hash for an empty text.
+func (h *Hash) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*h = 0
+
+		return nil
+	}
+
+	id, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*h = id
+
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, and encodes the hash function as a JSON string of its canonical name, or the
+// zero Hash as null.
+func (h Hash) MarshalJSON() ([]byte, error) {
+	if h == 0 {
+		return []byte("null"), nil
+	}
+
+	text, err := h.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, and decodes a JSON string as accepted by UnmarshalText. As for other
+// types, null leaves h unchanged, and therefore decodes the output of MarshalJSON for the zero Hash.
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	return h.UnmarshalText([]byte(name))
+}
+
+// Set implements flag.Value, such that a Hash can be used as a command line flag, e.g. with flag.Var.
+func (h *Hash) Set(name string) error {
+	return h.UnmarshalText([]byte(name))
+}
@@ -6,6 +6,8 @@
 // LICENSE file in the root directory of this source tree or at
 // https://spdx.org/licenses/MIT.html
 
+//go:build !hash_nosha2 && !hash_nosha3 && !hash_noblake2 && !hash_noblake3
+
 package hash_test
 
 import (
@@ -127,3 +129,36 @@ func Example_info() {
 	// Underlying block size (bytes): 0
 	// NOTE that the block size is only relevant for fixed output length functions, and is set to 0 for XOF
 }
+
+// Example_keyed shows how to compute a MAC with a hash function's native keyed mode.
+func Example_keyed() {
+	message := []byte("message")
+	key := []byte("key")
+	h := hash.BLAKE2B_256
+
+	keyed, err := h.NewKeyed(key)
+	if err != nil {
+		panic(err)
+	}
+
+	mac := keyed.Hash(0, message)
+	fmt.Printf("Keyed %s of (%s,%s) = %s\n", h, message, key, hex.EncodeToString(mac))
+
+	// Output: Keyed BLAKE2b-256 of (message,key) = a1b1483fd337f6b23318a53b756ad49d2541f3052ffb67515203dd7951b6e94e
+}
+
+// Example_clone shows how to snapshot a running hash, e.g. a protocol transcript, without disturbing it.
+func Example_clone() {
+	transcript := hash.SHA256.New()
+	_, _ = transcript.Write([]byte("ClientHello"))
+
+	// Snapshot the transcript hash at this point, without disturbing the running hash.
+	snapshot := transcript.Clone()
+	_, _ = transcript.Write([]byte("ServerHello"))
+
+	fmt.Printf("ClientHello: %x\n", snapshot.Sum(nil))
+	fmt.Printf("ClientHello || ServerHello: %x\n", transcript.Sum(nil))
+
+	// Output: ClientHello: b001745d730d53a71b509ee6ed8a09d57c5cd2ebad255f8aafda37d88dc2d836
+	// ClientHello || ServerHello: 1314fc0610c6d9b5ef3668f71239998a8a65a21ad377490bc391888ac80c56a7
+}
@@ -0,0 +1,139 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"errors"
+)
+
+const (
+	// dstOversizePrefix is prepended to domain separation tags longer than dstMaxLength before hashing them.
+	dstOversizePrefix = "H2C-OVERSIZE-DST-"
+
+	// dstMaxLength is the maximum length in bytes of a domain separation tag.
+	dstMaxLength = 255
+
+	// expandMaxLength is the maximum number of bytes that can be requested from message expansion.
+	expandMaxLength = 65535
+
+	// xmdMaxBlocks is the maximum number of hash outputs expand_message_xmd can produce.
+	xmdMaxBlocks = 255
+)
+
+var (
+	// ErrEmptyDST indicates that the domain separation tag is empty.
+	ErrEmptyDST = errors.New("empty domain separation tag")
+
+	// ErrExpandLength indicates that the requested message expansion length is 0 or exceeds the limits of RFC 9380.
+	ErrExpandLength = errors.New("invalid message expansion length")
+)
+
+// ExpandMessage implements expand_message from RFC 9380, and returns length bytes expanded from input and the domain
+// separation tag dst. It uses expand_message_xmd for FixedOutputLength functions, and expand_message_xof for
+// ExtendableOutputFunction functions.
+func (h Hash) ExpandMessage(input, dst []byte, length uint) ([]byte, error) {
+	switch h.Type() {
+	case FixedOutputLength:
+		return h.GetHashFunction().ExpandXMD(input, dst, length)
+	case ExtendableOutputFunction:
+		return h.GetXOF().ExpandXOF(input, dst, length)
+	default:
+		return nil, ErrUnavailable
+	}
+}
+
+// dstPrime returns DST || I2OSP(len(DST), 1).
+func dstPrime(dst []byte) []byte {
+	p := make([]byte, len(dst)+1)
+	copy(p, dst)
+	p[len(dst)] = byte(len(dst))
+
+	return p
+}
+
+// i2osp2 returns the 2-byte big-endian encoding of length.
+func i2osp2(length uint) []byte {
+	return []byte{byte(length >> 8), byte(length)}
+}
+
+// ExpandXMD implements expand_message_xmd from RFC 9380, and returns length bytes expanded from input and the domain
+// separation tag dst. Domain separation tags longer than 255 bytes are hashed first, as per the RFC. It returns
+// ErrEmptyDST if dst is empty, and ErrExpandLength if length is 0, larger than 65535, or larger than 255 hash outputs.
+// The current hash state is reset.
+func (h *Fixed) ExpandXMD(input, dst []byte, length uint) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+
+	size := uint(h.Size())
+	ell := (length + size - 1) / size
+
+	if length == 0 || length > expandMaxLength || ell > xmdMaxBlocks {
+		return nil, ErrExpandLength
+	}
+
+	if len(dst) > dstMaxLength {
+		dst = h.Hash(0, []byte(dstOversizePrefix), dst)
+	}
+
+	dst = dstPrime(dst)
+	zPad := make([]byte, h.BlockSize())
+
+	b0 := h.Hash(0, zPad, input, i2osp2(length), []byte{0}, dst)
+	bi := h.Hash(0, b0, []byte{1}, dst)
+
+	uniform := make([]byte, 0, ell*size)
+	uniform = append(uniform, bi...)
+
+	for i := uint(2); i <= ell; i++ {
+		xored := make([]byte, size)
+		for j := range xored {
+			xored[j] = b0[j] ^ bi[j]
+		}
+
+		bi = h.Hash(0, xored, []byte{byte(i)}, dst)
+		uniform = append(uniform, bi...)
+	}
+
+	return uniform[:length], nil
+}
+
+// ExpandXOF implements expand_message_xof from RFC 9380, and returns length bytes expanded from input and the domain
+// separation tag dst. Domain separation tags longer than 255 bytes are hashed first, as per the RFC, to a length of
+// twice the function's security level. It returns ErrEmptyDST if dst is empty, and ErrExpandLength if length is 0 or
+// larger than 65535. The current hash state is reset.
+func (h *ExtendableHash) ExpandXOF(input, dst []byte, length uint) ([]byte, error) {
+	if len(dst) == 0 {
+		return nil, ErrEmptyDST
+	}
+
+	if length == 0 || length > expandMaxLength {
+		return nil, ErrExpandLength
+	}
+
+	if len(dst) > dstMaxLength {
+		dst = h.read(uint((2*h.id.SecurityLevel()+7)/8), []byte(dstOversizePrefix), dst)
+	}
+
+	return h.read(length, input, i2osp2(length), dstPrime(dst)), nil
+}
+
+// read resets the hash, absorbs input, and squeezes length bytes, without enforcing a minimum output size.
+func (h *ExtendableHash) read(length uint, input ...[]byte) []byte {
+	h.Reset()
+
+	for _, i := range input {
+		_, _ = h.Write(i)
+	}
+
+	output := make([]byte, length)
+	_, _ = h.xof.Read(output)
+
+	return output
+}
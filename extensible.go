@@ -11,28 +11,13 @@ package hash
 import (
 	"errors"
 	"io"
-
-	"golang.org/x/crypto/blake2b"
-	"golang.org/x/crypto/blake2s"
-	"golang.org/x/crypto/sha3"
 )
 
-const (
-	// string IDs for the hash functions.
-	shake128 = "SHAKE128"
-	shake256 = "SHAKE256"
-	blake2xb = "BLAKE2XB"
-	blake2xs = "BLAKE2XS"
-
-	// block size in bytes.
-	blockSHAKE128 = 1344 / 8
-	blockSHAKE256 = 1088 / 8
-)
+// ErrSmallOutputSize indicates that the requested output size is smaller than the hash function's standard output size.
+var ErrSmallOutputSize = errors.New("requested output size too small")
 
-var errSmallOutputSize = errors.New("requested output size too small")
-
-// xof defines the interface to hash functions that support arbitrary-length output.
-type xof interface {
+// XOF defines the interface to hash functions that support arbitrary-length output.
+type XOF interface {
 	// Writer Write absorbs more data into the hash's state. It panics if called
 	// after Read.
 	io.Writer
@@ -45,25 +30,31 @@ type xof interface {
 	Reset()
 }
 
-func newXOF(hid Hash) newHash {
+// xof is embedded in ExtendableHash without exporting the underlying XOF.
+type xof = XOF
+
+func newXOF(hid Hash, xofFunc func() XOF) newHash {
 	return func() Hasher {
-		ext := &ExtendableHash{
-			xof: nil,
-			id:  hid,
+		return &ExtendableHash{
+			xof:      xofFunc(),
+			id:       hid,
+			standard: true,
 		}
+	}
+}
 
-		switch hid {
-		case SHAKE128:
-			ext.xof = sha3.NewShake128()
-		case SHAKE256:
-			ext.xof = sha3.NewShake256()
-		case BLAKE2XB:
-			ext.xof, _ = blake2b.NewXOF(blake2b.OutputLengthUnknown, nil)
-		case BLAKE2XS:
-			ext.xof, _ = blake2s.NewXOF(blake2s.OutputLengthUnknown, nil)
+// newKeyedXOF returns a constructor for the keyed mode of an extendable output function.
+func newKeyedXOF(hid Hash, keyed func(key []byte) (XOF, error)) newKeyedHash {
+	return func(key []byte) (Hasher, error) {
+		x, err := keyed(key)
+		if err != nil {
+			return nil, ErrKeySize
 		}
 
-		return ext
+		return &ExtendableHash{
+			xof: x,
+			id:  hid,
+		}, nil
 	}
 }
 
@@ -72,6 +63,9 @@ func newXOF(hid Hash) newHash {
 type ExtendableHash struct {
 	xof
 	id Hash
+
+	// standard is set for unkeyed and uncustomized instances of registered functions, which can be pooled.
+	standard bool
 }
 
 // Algorithm returns the Hash function identifier.
@@ -80,27 +74,67 @@ func (h *ExtendableHash) Algorithm() Hash {
 }
 
 // Hash returns the hash of the input argument with size output length.
+// It panics with ErrSmallOutputSize if size is smaller than the standard output size.
 func (h *ExtendableHash) Hash(size uint, input ...[]byte) []byte {
+	output, err := h.HashE(size, input...)
+	if err != nil {
+		panic(err)
+	}
+
+	return output
+}
+
+// HashE returns the hash of the input argument with size output length,
+// or ErrSmallOutputSize if size is smaller than the standard output size.
+func (h *ExtendableHash) HashE(size uint, input ...[]byte) ([]byte, error) {
+	h.Reset()
+
+	for _, i := range input {
+		_, _ = h.Write(i)
+	}
+
+	return h.ReadE(int(size))
+}
+
+// HashInto hashes the concatenation of input and fills dst with output, without allocating, and returns dst.
+// It panics with ErrSmallOutputSize if dst is shorter than the standard output size.
+func (h *ExtendableHash) HashInto(dst []byte, input ...[]byte) []byte {
+	if len(dst) < h.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
 	h.Reset()
 
 	for _, i := range input {
 		_, _ = h.Write(i)
 	}
 
-	return h.Read(int(size))
+	return h.SumInto(dst)
 }
 
 // Read consumes and returns size bytes from the current hash.
+// It panics with ErrSmallOutputSize if size is smaller than the standard output size.
 func (h *ExtendableHash) Read(size int) []byte {
+	output, err := h.ReadE(size)
+	if err != nil {
+		panic(err)
+	}
+
+	return output
+}
+
+// ReadE consumes and returns size bytes from the current hash,
+// or ErrSmallOutputSize if size is smaller than the standard output size.
+func (h *ExtendableHash) ReadE(size int) ([]byte, error) {
 	// This might be pulled in back later
 	if size < h.Size() {
-		panic(errSmallOutputSize)
+		return nil, ErrSmallOutputSize
 	}
 
 	output := make([]byte, size)
 	_, _ = h.xof.Read(output)
 
-	return output
+	return output, nil
 }
 
 // Write implements io.Writer.
@@ -117,6 +151,18 @@ func (h *ExtendableHash) Sum(prefix []byte) []byte {
 	return output
 }
 
+// SumInto consumes len(dst) bytes from the current hash into dst, without allocating, and returns dst.
+// It panics with ErrSmallOutputSize if dst is shorter than the standard output size.
+func (h *ExtendableHash) SumInto(dst []byte) []byte {
+	if len(dst) < h.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
+	_, _ = h.xof.Read(dst)
+
+	return dst
+}
+
 // Reset resets the hash to its initial state.
 func (h *ExtendableHash) Reset() {
 	h.xof.Reset()
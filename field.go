@@ -0,0 +1,68 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"errors"
+	"math/big"
+)
+
+// ErrInvalidModulus indicates that the field modulus is nil or smaller than 2.
+var ErrInvalidModulus = errors.New("invalid field modulus")
+
+// FieldElementLength returns the number of bytes L used by HashToField to derive one field element component modulo
+// the given modulus, with the hash function's security level as k. See FieldElementLengthK.
+func (h Hash) FieldElementLength(modulus *big.Int) uint {
+	return FieldElementLengthK(modulus, uint(h.SecurityLevel()))
+}
+
+// FieldElementLengthK returns the number of bytes L used by HashToFieldK to derive one field element component modulo
+// the given modulus: L = ceil((ceil(log2(modulus)) + k) / 8), where k is the target security level in bits.
+func FieldElementLengthK(modulus *big.Int, k uint) uint {
+	return (uint(modulus.BitLen()) + k + 7) / 8
+}
+
+// HashToField implements hash_to_field from RFC 9380 with the hash function's security level as the security
+// parameter k, which matches the RFC 9380 suites whose k equals h.SecurityLevel(). Other suites, e.g.
+// edwards25519_XMD:SHA-512_ELL2_RO_ with k = 128, must use HashToFieldK, which documents the parameters and errors.
+func (h Hash) HashToField(input, dst []byte, count, ext uint, modulus *big.Int) ([][]*big.Int, error) {
+	return h.HashToFieldK(input, dst, count, ext, uint(h.SecurityLevel()), modulus)
+}
+
+// HashToFieldK implements hash_to_field from RFC 9380, and returns count elements of the field of extension degree
+// ext over the prime field of the given modulus, each element being a slice of its ext components. The input is
+// expanded using ExpandMessage with the domain separation tag dst, and the security parameter k, in bits, determines
+// the bias of the reduction. It returns ErrInvalidModulus if the modulus is invalid, and the errors of ExpandMessage
+// otherwise.
+func (h Hash) HashToFieldK(input, dst []byte, count, ext, k uint, modulus *big.Int) ([][]*big.Int, error) {
+	if modulus == nil || modulus.Cmp(big.NewInt(1)) <= 0 {
+		return nil, ErrInvalidModulus
+	}
+
+	length := FieldElementLengthK(modulus, k)
+
+	uniform, err := h.ExpandMessage(input, dst, count*ext*length)
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([][]*big.Int, count)
+
+	for i := range elements {
+		elements[i] = make([]*big.Int, ext)
+
+		for j := range elements[i] {
+			offset := length * (uint(j) + uint(i)*ext)
+			e := new(big.Int).SetBytes(uniform[offset : offset+length])
+			elements[i][j] = e.Mod(e, modulus)
+		}
+	}
+
+	return elements, nil
+}
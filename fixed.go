@@ -10,49 +10,61 @@ package hash
 
 import (
 	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/sha512"
 	"errors"
 	"hash"
 	"io"
 
 	"golang.org/x/crypto/hkdf"
-	"golang.org/x/crypto/sha3"
 )
 
-const (
-	// block size in bytes.
-	blockSHA3256 = 1088 / 8
-	blockSHA3384 = 832 / 8
-	blockSHA3512 = 576 / 8
-)
+var (
+	// ErrHmacKeySize indicates that the HMAC key is longer than the hash function's output size.
+	ErrHmacKeySize = errors.New("hmac key length is larger than hash output size")
 
-var errHmacKeySize = errors.New("hmac key length is larger than hash output size")
+	// ErrHKDFLength indicates that the requested HKDF output length is negative or larger than 255 times the hash
+	// function's output size.
+	ErrHKDFLength = errors.New("invalid hkdf output length")
+)
 
-func newFixed(hid Hash) newHash {
-	var hashFunc func() hash.Hash
+// hkdfMaxBlocks is the maximum number of hash outputs HKDF-Expand can produce, as per RFC 5869.
+const hkdfMaxBlocks = 255
 
-	switch hid {
-	case SHA256:
-		hashFunc = sha256.New
-	case SHA384:
-		hashFunc = sha512.New384
-	case SHA512:
-		hashFunc = sha512.New
-	case SHA3_256:
-		hashFunc = sha3.New256
-	case SHA3_384:
-		hashFunc = sha3.New384
-	case SHA3_512:
-		hashFunc = sha3.New512
+func newFixed(hid Hash, hashFunc func() hash.Hash) newHash {
+	return func() Hasher {
+		return &Fixed{
+			id:       hid,
+			hash:     hashFunc(),
+			f:        hashFunc,
+			standard: true,
+		}
 	}
+}
+
+// newKeyedFixed returns a constructor for the keyed mode of a fixed output length hash function. The key is copied,
+// and Reset on the returned Hasher preserves it.
+func newKeyedFixed(hid Hash, keyed func(key []byte) (hash.Hash, error)) newKeyedHash {
+	return func(key []byte) (Hasher, error) {
+		if len(key) == 0 {
+			return nil, ErrKeySize
+		}
+
+		k := make([]byte, len(key))
+		copy(k, key)
+
+		if _, err := keyed(k); err != nil {
+			return nil, ErrKeySize
+		}
+
+		hashFunc := func() hash.Hash {
+			h, _ := keyed(k)
+			return h
+		}
 
-	return func() Hasher {
 		return &Fixed{
 			id:   hid,
 			hash: hashFunc(),
 			f:    hashFunc,
-		}
+		}, nil
 	}
 }
 
@@ -61,6 +73,9 @@ type Fixed struct {
 	hash hash.Hash
 	f    func() hash.Hash
 	id   Hash
+
+	// standard is set for unkeyed instances of registered functions, which can be pooled.
+	standard bool
 }
 
 // Algorithm returns the Hash function identifier.
@@ -79,6 +94,22 @@ func (h *Fixed) Hash(_ uint, input ...[]byte) []byte {
 	return h.Sum(nil)
 }
 
+// HashInto hashes the concatenation of input and writes the Size bytes of output at the beginning of dst, without
+// allocating, and returns them. It panics with ErrSmallOutputSize if dst is shorter than Size.
+func (h *Fixed) HashInto(dst []byte, input ...[]byte) []byte {
+	if len(dst) < h.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
+	h.Reset()
+
+	for _, i := range input {
+		_, _ = h.Write(i)
+	}
+
+	return h.SumInto(dst)
+}
+
 // Read returns size bytes from the current hash.
 // It does not change the underlying hash state.
 func (h *Fixed) Read(_ int) []byte {
@@ -96,6 +127,16 @@ func (h *Fixed) Sum(prefix []byte) []byte {
 	return h.hash.Sum(prefix)
 }
 
+// SumInto writes the Size bytes of the current hash at the beginning of dst, without allocating, and returns them.
+// It does not change the underlying hash state, and panics with ErrSmallOutputSize if dst is shorter than Size.
+func (h *Fixed) SumInto(dst []byte) []byte {
+	if len(dst) < h.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
+	return h.hash.Sum(dst[:0])
+}
+
 // Reset resets the hash to its initial state.
 func (h *Fixed) Reset() {
 	h.hash.Reset()
@@ -121,31 +162,57 @@ func (h *Fixed) GetXOF() *ExtendableHash {
 	return nil
 }
 
-// Hmac wraps the built-in hmac.
+// Hmac wraps the built-in hmac. It panics with ErrHmacKeySize if the key is longer than the hash output size.
 func (h *Fixed) Hmac(message, key []byte) []byte {
+	mac, err := h.HmacE(message, key)
+	if err != nil {
+		panic(err)
+	}
+
+	return mac
+}
+
+// HmacE wraps the built-in hmac, and returns ErrHmacKeySize if the key is longer than the hash output size.
+func (h *Fixed) HmacE(message, key []byte) ([]byte, error) {
 	if len(key) > h.id.Size() {
-		panic(errHmacKeySize)
+		return nil, ErrHmacKeySize
 	}
 
 	hm := hmac.New(h.f, key)
 	_, _ = hm.Write(message)
 
-	return hm.Sum(nil)
+	return hm.Sum(nil), nil
 }
 
 // HKDF is an "extract-then-expand" HMAC based Key derivation function,
-// where info is the specific usage identifying information.
+// where info is the specific usage identifying information. A length of 0 defaults to the hash output size.
+// It panics with ErrHKDFLength if length is negative or larger than 255 times the hash output size, rather than
+// returning a key whose bytes beyond the maximum HKDF output are zeros. Use HKDFE to handle the error instead.
 func (h *Fixed) HKDF(secret, salt, info []byte, length int) []byte {
-	if length == 0 {
-		length = h.id.Size()
+	key, err := h.HKDFE(secret, salt, info, length)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// HKDFE is an "extract-then-expand" HMAC based Key derivation function, where info is the specific usage identifying
+// information. It returns ErrHKDFLength if length is negative or larger than 255 times the hash output size.
+func (h *Fixed) HKDFE(secret, salt, info []byte, length int) ([]byte, error) {
+	length, err := h.hkdfLength(length)
+	if err != nil {
+		return nil, err
 	}
 
 	kdf := hkdf.New(h.f, secret, salt, info)
 	dst := make([]byte, length)
 
-	_, _ = io.ReadFull(kdf, dst)
+	if _, err = io.ReadFull(kdf, dst); err != nil {
+		return nil, err
+	}
 
-	return dst
+	return dst, nil
 }
 
 // HKDFExtract is an "extract" only HKDF, where the secret and salt are used to generate a pseudorandom key. This key
@@ -155,16 +222,46 @@ func (h *Fixed) HKDFExtract(secret, salt []byte) []byte {
 }
 
 // HKDFExpand is an "expand" only HKDF, where the key should be an already random/hashed input,
-// and info specific key usage identifying information.
+// and info specific key usage identifying information. A length of 0 defaults to the hash output size.
+// It panics with ErrHKDFLength if length is negative or larger than 255 times the hash output size, rather than
+// returning a key whose bytes beyond the maximum HKDF output are zeros. Use HKDFExpandE to handle the error instead.
 func (h *Fixed) HKDFExpand(pseudorandomKey, info []byte, length int) []byte {
-	if length == 0 {
-		length = h.id.Size()
+	key, err := h.HKDFExpandE(pseudorandomKey, info, length)
+	if err != nil {
+		panic(err)
+	}
+
+	return key
+}
+
+// HKDFExpandE is an "expand" only HKDF, where the key should be an already random/hashed input, and info specific key
+// usage identifying information. It returns ErrHKDFLength if length is negative or larger than 255 times the hash
+// output size.
+func (h *Fixed) HKDFExpandE(pseudorandomKey, info []byte, length int) ([]byte, error) {
+	length, err := h.hkdfLength(length)
+	if err != nil {
+		return nil, err
 	}
 
 	kdf := hkdf.Expand(h.f, pseudorandomKey, info)
 	dst := make([]byte, length)
 
-	_, _ = kdf.Read(dst)
+	if _, err = io.ReadFull(kdf, dst); err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// hkdfLength returns the output length to use for HKDF, defaulting to the hash output size if length is 0.
+func (h *Fixed) hkdfLength(length int) (int, error) {
+	if length == 0 {
+		return h.id.Size(), nil
+	}
+
+	if length < 0 || length > hkdfMaxBlocks*h.id.Size() {
+		return 0, ErrHKDFLength
+	}
 
-	return dst
+	return length, nil
 }
@@ -2,6 +2,6 @@ module github.com/bytemare/hash
 
 go 1.22.2
 
-require golang.org/x/crypto v0.22.0
+require golang.org/x/crypto v0.24.0
 
-require golang.org/x/sys v0.19.0 // indirect
+require golang.org/x/sys v0.21.0 // indirect
@@ -7,18 +7,43 @@
 // https://spdx.org/licenses/MIT.html
 
 // Package hash is a small wrapper around built-in cryptographic hash functions to make their usage easier.
+//
+// All built-in hash functions are registered by default. To reduce the size of binaries, e.g. for embedded or WASM
+// targets, the families that are not needed can be excluded with the following build tags, such that their
+// implementations are not linked into the binary and Available reports false for them:
+//
+//   - hash_nosha2 excludes SHA-2.
+//   - hash_nosha3 excludes SHA-3, SHAKE, and the NIST SP 800-185 functions (cSHAKE, KMAC, TupleHash, and
+//     ParallelHash), whose methods are then not defined.
+//   - hash_noblake2 excludes BLAKE2b, BLAKE2s, BLAKE2XB, and BLAKE2XS.
+//   - hash_noblake3 excludes BLAKE3, and NewBLAKE3DeriveKey is then not defined.
 package hash
 
 import (
 	"crypto"
-	"crypto/sha256"
-	"crypto/sha512"
+	"errors"
+	"hash"
 	"io"
+	"math"
+)
+
+var (
+	// ErrUnavailable indicates that the hash function is not registered or not linked into the binary.
+	ErrUnavailable = errors.New("hash function is not available")
+
+	// ErrNoKeyedMode indicates that the hash function has no native keyed mode.
+	ErrNoKeyedMode = errors.New("hash function has no native keyed mode")
+
+	// ErrKeySize indicates that the key size is not supported by the hash function's keyed mode.
+	ErrKeySize = errors.New("invalid key size")
 )
 
 type Hash uint8
 
 const (
+	// SHA224 identifies the Sha2 hashing function with 224 bit output.
+	SHA224 = Hash(crypto.SHA224)
+
 	// SHA256 identifies the Sha2 hashing function with 256 bit output.
 	SHA256 = Hash(crypto.SHA256)
 
@@ -28,6 +53,15 @@ const (
 	// SHA512 identifies the Sha2 hashing function with 512 bit output.
 	SHA512 = Hash(crypto.SHA512)
 
+	// SHA512_224 identifies the Sha2 hashing function with 224 bit output, truncated from SHA-512.
+	SHA512_224 = Hash(crypto.SHA512_224)
+
+	// SHA512_256 identifies the Sha2 hashing function with 256 bit output, truncated from SHA-512.
+	SHA512_256 = Hash(crypto.SHA512_256)
+
+	// SHA3_224 identifies the Sha3 hashing function with 224 bit output.
+	SHA3_224 = Hash(crypto.SHA3_224)
+
 	// SHA3_256 identifies the Sha3 hashing function with 256 bit output.
 	SHA3_256 = Hash(crypto.SHA3_256)
 
@@ -37,6 +71,18 @@ const (
 	// SHA3_512 identifies the Sha3 hashing function with 512 bit output.
 	SHA3_512 = Hash(crypto.SHA3_512)
 
+	// BLAKE2B_256 identifies the BLAKE2b hashing function with 256 bit output.
+	BLAKE2B_256 = Hash(crypto.BLAKE2b_256)
+
+	// BLAKE2B_384 identifies the BLAKE2b hashing function with 384 bit output.
+	BLAKE2B_384 = Hash(crypto.BLAKE2b_384)
+
+	// BLAKE2B_512 identifies the BLAKE2b hashing function with 512 bit output.
+	BLAKE2B_512 = Hash(crypto.BLAKE2b_512)
+
+	// BLAKE2S_256 identifies the BLAKE2s hashing function with 256 bit output.
+	BLAKE2S_256 = Hash(crypto.BLAKE2s_256)
+
 	maxFixed = 20
 
 	// SHAKE128 identifies the SHAKE128 Extendable-Output Function.
@@ -51,7 +97,13 @@ const (
 	// BLAKE2XS identifies the BLAKE2XS Extendable-Output Function.
 	BLAKE2XS Hash = maxFixed + 4
 
-	maxID Hash = maxFixed + 5
+	// BLAKE3 identifies the BLAKE3 Extendable-Output Function.
+	BLAKE3 Hash = maxFixed + 5
+
+	maxID Hash = maxFixed + 6
+
+	// maxRegistry is the number of identifiers a Hash can hold, including those allocated by Register.
+	maxRegistry = math.MaxUint8 + 1
 )
 
 // FromCrypto returns a Hashing identifier given a hash function defined in the built-in crypto,
@@ -65,19 +117,48 @@ func FromCrypto(h crypto.Hash) Hash {
 	return 0
 }
 
-// Available reports whether the given hash function is linked into the binary.
+// Available reports whether the given hash function is linked into the binary, i.e. whether it is a built-in function
+// that was not excluded with a build tag, or a function registered with Register.
 func (h Hash) Available() bool {
-	return h < maxID && registeredHashes[h]
+	return registeredHashes[h]
 }
 
-// Hash returns the hash of the concatenated input.
+// Hash returns the hash of the concatenated input. It panics if the hash function is not available.
 func (h Hash) Hash(input ...[]byte) []byte {
-	return h.New().Hash(uint(h.Size()), input...)
+	output, err := h.HashE(input...)
+	if err != nil {
+		panic(err)
+	}
+
+	return output
+}
+
+// HashE returns the hash of the concatenated input, or ErrUnavailable if the hash function is not available.
+func (h Hash) HashE(input ...[]byte) ([]byte, error) {
+	if !h.Available() {
+		return nil, ErrUnavailable
+	}
+
+	return h.HashInto(make([]byte, h.Size()), input...), nil
 }
 
-// New returns the underlying Hasher function.
+// New returns the underlying Hasher function. It panics if the hash function is not available.
 func (h Hash) New() Hasher {
-	return hashes[h]()
+	hasher, err := h.NewE()
+	if err != nil {
+		panic(err)
+	}
+
+	return hasher
+}
+
+// NewE returns the underlying Hasher function, or ErrUnavailable if the hash function is not available.
+func (h Hash) NewE() (Hasher, error) {
+	if !h.Available() {
+		return nil, ErrUnavailable
+	}
+
+	return hashes[h](), nil
 }
 
 // String returns the Hash functions name.
@@ -102,14 +183,26 @@ func (h Hash) SecurityLevel() int {
 
 // Type returns the hash function's type.
 func (h Hash) Type() Type {
-	switch {
-	case SHA256 <= h && h < maxFixed && h.Available():
-		return FixedOutputLength
-	case maxFixed < h && h < maxID && h.Available():
-		return ExtendableOutputFunction
+	if !h.Available() {
+		return ""
+	}
+
+	return types[h]
+}
+
+// NewKeyed returns a Hasher using the hash function's native keyed mode, which can be used as a MAC without HMAC.
+// It returns ErrUnavailable if the hash function is not available, ErrNoKeyedMode if it has no native keyed mode, and
+// ErrKeySize if the key size is not supported.
+func (h Hash) NewKeyed(key []byte) (Hasher, error) {
+	if !h.Available() {
+		return nil, ErrUnavailable
 	}
 
-	return ""
+	if keyedHashes[h] == nil {
+		return nil, ErrNoKeyedMode
+	}
+
+	return keyedHashes[h](key)
 }
 
 // GetHashFunction returns the underlying Fixed Hasher for FixedOutputLength functions, and nil otherwise.
@@ -151,6 +244,10 @@ type Hasher interface {
 	// BlockSize returns the hash's underlying block size.
 	BlockSize() int
 
+	// Clone returns an independent copy of the Hasher in its current state, including the output already read from
+	// extendable output functions. It panics with ErrNotClonable if the underlying state can't be copied.
+	Clone() Hasher
+
 	// GetHashFunction returns the underlying Fixed Hasher for FixedOutputLength functions, and nil otherwise.
 	GetHashFunction() *Fixed
 
@@ -172,41 +269,52 @@ var (
 	size256 = 32
 
 	// security level in bits.
+	sec112 = 112
 	sec128 = 128
 	sec192 = 192
 	sec224 = 224
 	sec256 = 256
 )
 
-type newHash func() Hasher
+type (
+	newHash      func() Hasher
+	newKeyedHash func(key []byte) (Hasher, error)
+)
 
 var (
-	registeredHashes = [maxID]bool{}
-	hashes           = [maxID]newHash{}
-	names            = [maxID]string{}
-	blockSizes       = [maxID]int{}
-	outputSizes      = [maxID]int{}
-	securityLevels   = [maxID]int{}
+	registeredHashes = [maxRegistry]bool{}
+	hashes           = [maxRegistry]newHash{}
+	keyedHashes      = [maxRegistry]newKeyedHash{}
+	types            = [maxRegistry]Type{}
+	names            = [maxRegistry]string{}
+	blockSizes       = [maxRegistry]int{}
+	outputSizes      = [maxRegistry]int{}
+	securityLevels   = [maxRegistry]int{}
 )
 
-func (h Hash) register(n func(Hash) newHash, name string, block, output, security int) {
+// registerBuiltin calls the registration function of a built-in family of hash functions, and is meant for package
+// variable initializers. These all complete before any init function runs, such that the families included in the
+// build are registered whatever the order in which files are initialized, e.g. for the power-on self-test.
+func registerBuiltin(register func()) struct{} {
+	register()
+	return struct{}{}
+}
+
+func register[C Constructor](h Hash, constructor C, name string, block, output, security int) {
+	switch c := any(constructor).(type) {
+	case func() hash.Hash:
+		hashes[h] = newFixed(h, c)
+		types[h] = FixedOutputLength
+	case func() XOF:
+		hashes[h] = newXOF(h, c)
+		types[h] = ExtendableOutputFunction
+	}
+
+	setPool(h, hashes[h])
+
 	registeredHashes[h] = true
-	hashes[h] = n(h)
 	names[h] = name
 	blockSizes[h] = block
 	outputSizes[h] = output
 	securityLevels[h] = security
 }
-
-func init() {
-	SHA256.register(newFixed, crypto.SHA256.String(), sha256.BlockSize, crypto.SHA256.Size(), sec128)
-	SHA384.register(newFixed, crypto.SHA384.String(), sha512.BlockSize, crypto.SHA384.Size(), sec192)
-	SHA512.register(newFixed, crypto.SHA512.String(), sha512.BlockSize, crypto.SHA512.Size(), sec256)
-	SHA3_256.register(newFixed, crypto.SHA3_256.String(), blockSHA3256, crypto.SHA3_256.Size(), sec128)
-	SHA3_384.register(newFixed, crypto.SHA3_384.String(), blockSHA3384, crypto.SHA3_384.Size(), sec192)
-	SHA3_512.register(newFixed, crypto.SHA3_512.String(), blockSHA3512, crypto.SHA3_512.Size(), sec256)
-	SHAKE128.register(newXOF, shake128, blockSHAKE128, size256, sec128)
-	SHAKE256.register(newXOF, shake256, blockSHAKE256, size256, sec224)
-	BLAKE2XB.register(newXOF, blake2xb, 0, size256, sec128)
-	BLAKE2XS.register(newXOF, blake2xs, 0, size256, sec128)
-}
@@ -0,0 +1,448 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+// Package hashtest provides a conformance test suite for implementations of the hash.Hasher interface of
+// github.com/bytemare/hash, e.g. wrappers that instrument, mock, or offload the built-in Hashers.
+package hashtest
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// defaultBlockSize is used to choose message lengths for functions that don't report a block size.
+const defaultBlockSize = 64
+
+// TestHasher checks that the Hashers returned by newHasher satisfy the hash.Hasher contract. Every call to newHasher
+// must return a new and independent Hasher in its initial state, and all must compute the same function, whose
+// Algorithm must be available, e.g. registered with hash.Register. Keyed or customized Hashers are supported, as the
+// outputs are only compared to the ones of other Hashers returned by newHasher.
+//
+// The suite checks the consistency of Size and BlockSize with the Algorithm, that Write never fails and that chunked
+// writes are equivalent to a single one, the Reset semantics, that Hash resets the state, that Sum and SumInto, if
+// implemented, don't modify the state of fixed output length functions, that they consume output from extendable
+// output functions like Read does, and that Clone, if supported, returns an independent copy.
+func TestHasher(t *testing.T, newHasher func() hash.Hasher) {
+	t.Helper()
+
+	h := newHasher()
+	if h == nil {
+		t.Fatal("newHasher returned nil")
+	}
+
+	s := &suite{
+		newHasher: newHasher,
+		algorithm: h.Algorithm(),
+	}
+
+	if !s.algorithm.Available() {
+		t.Fatalf("the Algorithm %d of the Hasher is not available", s.algorithm)
+	}
+
+	s.size = s.algorithm.Size()
+	s.lengths = messageLengths(s.algorithm.BlockSize())
+
+	t.Run("Metadata", s.testMetadata)
+	t.Run("Write", s.testWrite)
+	t.Run("Reset", s.testReset)
+	t.Run("Hash", s.testHash)
+
+	if s.algorithm.Type() == hash.FixedOutputLength {
+		t.Run("Sum", s.testFixedSum)
+		t.Run("SumInto", s.testFixedSumInto)
+	} else {
+		t.Run("Sum", s.testXOFSum)
+		t.Run("SumInto", s.testXOFSumInto)
+	}
+
+	t.Run("Clone", s.testClone)
+}
+
+type suite struct {
+	newHasher func() hash.Hasher
+	lengths   []int
+	algorithm hash.Hash
+	size      int
+}
+
+// messageLengths returns message lengths around the block size, and spanning several blocks.
+func messageLengths(blockSize int) []int {
+	if blockSize <= 0 {
+		blockSize = defaultBlockSize
+	}
+
+	return []int{0, 1, blockSize - 1, blockSize, blockSize + 1, 3*blockSize + 5, 4099}
+}
+
+// message returns a deterministic message of length bytes.
+func message(length int) []byte {
+	m := make([]byte, length)
+	for i := range m {
+		m[i] = byte(i % 251)
+	}
+
+	return m
+}
+
+// output returns the first length bytes of the output of a new Hasher over the concatenated input, using only Write
+// and Read. The length is ignored for fixed output length functions.
+func (s *suite) output(length int, input ...[]byte) []byte {
+	h := s.newHasher()
+	for _, i := range input {
+		_, _ = h.Write(i)
+	}
+
+	return h.Read(length)
+}
+
+// digest returns the standard size output of a new Hasher over the concatenated input.
+func (s *suite) digest(input ...[]byte) []byte {
+	return s.output(s.size, input...)
+}
+
+// panics returns the value f panics with, or nil.
+func panics(f func()) (p any) {
+	defer func() {
+		p = recover()
+	}()
+
+	f()
+
+	return nil
+}
+
+// expectPanic reports an error if f doesn't panic with err.
+func expectPanic(t *testing.T, name string, err error, f func()) {
+	t.Helper()
+
+	p := panics(f)
+	if e, ok := p.(error); !ok || !errors.Is(e, err) {
+		t.Errorf("%s: expected panic with %q, got %v", name, err, p)
+	}
+}
+
+func (s *suite) testMetadata(t *testing.T) {
+	h := s.newHasher()
+
+	if h.Algorithm() != s.algorithm {
+		t.Errorf("Algorithm: got %s, expected %s", h.Algorithm(), s.algorithm)
+	}
+
+	if h.Size() != s.size || h.Size() <= 0 {
+		t.Errorf("Size: got %d, expected %s's %d", h.Size(), s.algorithm, s.size)
+	}
+
+	if h.BlockSize() != s.algorithm.BlockSize() {
+		t.Errorf("BlockSize: got %d, expected %s's %d", h.BlockSize(), s.algorithm, s.algorithm.BlockSize())
+	}
+
+	fixed, xof := h.GetHashFunction(), h.GetXOF()
+
+	switch s.algorithm.Type() {
+	case hash.FixedOutputLength:
+		if fixed == nil || xof != nil {
+			t.Errorf("expected only GetHashFunction to return a Hasher for %s", s.algorithm)
+		}
+
+		if fixed != nil && fixed.Algorithm() != s.algorithm {
+			t.Errorf("GetHashFunction: got %s, expected %s", fixed.Algorithm(), s.algorithm)
+		}
+	case hash.ExtendableOutputFunction:
+		if xof == nil || fixed != nil {
+			t.Errorf("expected only GetXOF to return a Hasher for %s", s.algorithm)
+		}
+
+		if xof != nil && xof.Algorithm() != s.algorithm {
+			t.Errorf("GetXOF: got %s, expected %s", xof.Algorithm(), s.algorithm)
+		}
+	default:
+		t.Errorf("unknown type %q for %s", s.algorithm.Type(), s.algorithm)
+	}
+}
+
+func (s *suite) testWrite(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		h := s.newHasher()
+
+		if n, err := h.Write(m); n != length || err != nil {
+			t.Errorf("Write of %d bytes returned (%d, %v)", length, n, err)
+		}
+
+		if n, err := h.Write(nil); n != 0 || err != nil {
+			t.Errorf("Write of nil returned (%d, %v)", n, err)
+		}
+
+		expected := h.Read(s.size)
+
+		// Any split of the input must be equivalent to a single write.
+		for _, chunk := range []int{1, 7, 64, 1000} {
+			h = s.newHasher()
+
+			for i := 0; i < length; i += chunk {
+				_, _ = h.Write(m[i:min(i+chunk, length)])
+			}
+
+			if out := h.Read(s.size); !bytes.Equal(out, expected) {
+				t.Errorf("writing %d bytes in chunks of %d: got %x, expected %x", length, chunk, out, expected)
+			}
+		}
+	}
+}
+
+func (s *suite) testReset(t *testing.T) {
+	empty := s.digest()
+
+	if out := s.newHasher().Sum(nil); !bytes.Equal(out, empty) {
+		t.Errorf("a new Hasher's Sum is %x, expected the empty input digest %x", out, empty)
+	}
+
+	for _, length := range s.lengths {
+		m := message(length)
+		expected := s.digest(m)
+		h := s.newHasher()
+
+		// Reset after writing, and after reading.
+		_, _ = h.Write(m)
+		h.Reset()
+
+		if out := h.Read(s.size); !bytes.Equal(out, empty) {
+			t.Errorf("Reset after %d bytes: got %x, expected the empty input digest %x", length, out, empty)
+		}
+
+		h.Reset()
+		_, _ = h.Write(m)
+
+		if out := h.Read(s.size); !bytes.Equal(out, expected) {
+			t.Errorf("Reset after Read, then %d bytes: got %x, expected %x", length, out, expected)
+		}
+	}
+}
+
+func (s *suite) testHash(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		h := s.newHasher()
+
+		// Hash must reset the state, and hash the concatenation of its arguments.
+		_, _ = h.Write([]byte("dirty state"))
+		half := length / 2
+
+		if out, expected := h.Hash(uint(s.size), m[:half], m[half:]), s.digest(m); !bytes.Equal(out, expected) {
+			t.Errorf("Hash of %d bytes: got %x, expected %x", length, out, expected)
+		}
+
+		if out, expected := h.Hash(uint(s.size), m), s.digest(m); !bytes.Equal(out, expected) {
+			t.Errorf("second Hash of %d bytes: got %x, expected %x", length, out, expected)
+		}
+
+		long := 3*s.size + 1
+		out := h.Hash(uint(long), m)
+
+		if s.algorithm.Type() == hash.FixedOutputLength {
+			// The size is ignored by fixed output length functions.
+			if expected := s.digest(m); !bytes.Equal(out, expected) || !bytes.Equal(h.Hash(0, m), expected) {
+				t.Errorf("Hash of %d bytes with a non-standard size: got %x, expected %x", length, out, expected)
+			}
+
+			continue
+		}
+
+		if expected := s.output(long, m); !bytes.Equal(out, expected) {
+			t.Errorf("Hash of %d bytes with %d bytes output: got %x, expected %x", length, long, out, expected)
+		}
+	}
+
+	if s.algorithm.Type() == hash.ExtendableOutputFunction {
+		expectPanic(t, "Hash with a small size", hash.ErrSmallOutputSize, func() {
+			_ = s.newHasher().Hash(uint(s.size - 1))
+		})
+	}
+}
+
+func (s *suite) testFixedSum(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		expected := s.digest(m)
+		h := s.newHasher()
+		_, _ = h.Write(m)
+
+		// Sum and Read must not modify the state.
+		for _, out := range [][]byte{h.Sum(nil), h.Sum(nil), h.Read(s.size), h.Read(0)} {
+			if !bytes.Equal(out, expected) {
+				t.Errorf("Sum of %d bytes: got %x, expected %x", length, out, expected)
+			}
+		}
+
+		prefix := []byte("prefix")
+		if out := h.Sum(prefix); !bytes.Equal(out[:len(prefix)], prefix) || !bytes.Equal(out[len(prefix):], expected) {
+			t.Errorf("Sum with a prefix: got %x, expected %x", out, append(prefix, expected...))
+		}
+
+		_, _ = h.Write(m)
+
+		if out, expected := h.Sum(nil), s.digest(m, m); !bytes.Equal(out, expected) {
+			t.Errorf("Write after Sum of %d bytes: got %x, expected %x", length, out, expected)
+		}
+	}
+}
+
+func (s *suite) testXOFSum(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		expected := s.output(4*s.size+1, m)
+		h := s.newHasher()
+		_, _ = h.Write(m)
+
+		// Sum, Read, and SumInto consume successive parts of the output stream.
+		out := h.Sum(nil)
+		out = append(out, h.Read(s.size)...)
+		out = append(out, h.Read(s.size+1)...)
+		out = h.Sum(out)
+
+		if !bytes.Equal(out, expected) {
+			t.Errorf("successive reads of %d bytes: got %x, expected %x", length, out, expected)
+		}
+	}
+
+	expectPanic(t, "Read with a small size", hash.ErrSmallOutputSize, func() {
+		_ = s.newHasher().Read(s.size - 1)
+	})
+}
+
+// sumIntoHasher is implemented by Hashers that also write their output into a given buffer, like the ones of the
+// hash package.
+type sumIntoHasher interface {
+	hash.Hasher
+	SumInto(dst []byte) []byte
+}
+
+// newSumIntoHasher returns a new Hasher, or skips the test if it does not implement SumInto.
+func (s *suite) newSumIntoHasher(t *testing.T) sumIntoHasher {
+	t.Helper()
+
+	h, ok := s.newHasher().(sumIntoHasher)
+	if !ok {
+		t.Skipf("%s: SumInto is not implemented", s.algorithm)
+	}
+
+	return h
+}
+
+func (s *suite) testFixedSumInto(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		expected := s.digest(m)
+		h := s.newSumIntoHasher(t)
+		_, _ = h.Write(m)
+
+		dst := make([]byte, s.size+8)
+
+		out := h.SumInto(dst)
+		if !bytes.Equal(out, expected) || &out[0] != &dst[0] {
+			t.Errorf("SumInto of %d bytes: got %x, expected %x in dst", length, out, expected)
+		}
+
+		if out = h.SumInto(dst[:s.size]); !bytes.Equal(out, expected) {
+			t.Errorf("second SumInto of %d bytes: got %x, expected %x", length, out, expected)
+		}
+	}
+
+	expectPanic(t, "SumInto with a small buffer", hash.ErrSmallOutputSize, func() {
+		_ = s.newSumIntoHasher(t).SumInto(make([]byte, s.size-1))
+	})
+}
+
+func (s *suite) testXOFSumInto(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		expected := s.output(5*s.size, m)
+		h := s.newSumIntoHasher(t)
+		_, _ = h.Write(m)
+
+		dst := make([]byte, 2*s.size)
+
+		out := h.SumInto(dst)
+		if !bytes.Equal(out, expected[:len(dst)]) || len(out) != len(dst) || &out[0] != &dst[0] {
+			t.Errorf("SumInto of %d bytes: got %x, expected %x in dst", length, out, expected[:len(dst)])
+		}
+
+		if out = h.SumInto(make([]byte, 3*s.size)); !bytes.Equal(out, expected[len(dst):]) {
+			t.Errorf("second SumInto of %d bytes: got %x, expected %x", length, out, expected[len(dst):])
+		}
+	}
+
+	expectPanic(t, "SumInto with a small buffer", hash.ErrSmallOutputSize, func() {
+		_ = s.newSumIntoHasher(t).SumInto(make([]byte, s.size-1))
+	})
+}
+
+// clone returns a clone of h, or skips the test if h is not clonable.
+func clone(t *testing.T, h hash.Hasher) hash.Hasher {
+	t.Helper()
+
+	var c hash.Hasher
+
+	if p := panics(func() { c = h.Clone() }); p != nil {
+		if err, ok := p.(error); ok && errors.Is(err, hash.ErrNotClonable) {
+			t.Skipf("%s: Clone is not supported", h.Algorithm())
+		}
+
+		panic(p)
+	}
+
+	return c
+}
+
+func (s *suite) testClone(t *testing.T) {
+	for _, length := range s.lengths {
+		m := message(length)
+		h := s.newHasher()
+		_, _ = h.Write(m)
+
+		c := clone(t, h)
+		if c.Algorithm() != s.algorithm {
+			t.Errorf("Clone: got %s, expected %s", c.Algorithm(), s.algorithm)
+		}
+
+		// The clone and the original evolve independently.
+		_, _ = h.Write(m)
+
+		if out, expected := c.Read(s.size), s.digest(m); !bytes.Equal(out, expected) {
+			t.Errorf("clone after %d bytes: got %x, expected %x", length, out, expected)
+		}
+
+		if out, expected := h.Read(s.size), s.digest(m, m); !bytes.Equal(out, expected) {
+			t.Errorf("original after %d bytes: got %x, expected %x", length, out, expected)
+		}
+	}
+
+	if s.algorithm.Type() == hash.FixedOutputLength {
+		return
+	}
+
+	// Clones of extendable output functions resume the output stream where it was.
+	m := message(s.lengths[len(s.lengths)-1])
+	expected := s.output(3*s.size, m)
+	h := s.newHasher()
+	_, _ = h.Write(m)
+	_ = h.Read(s.size)
+
+	c := clone(t, h)
+
+	if out := c.Read(2 * s.size); !bytes.Equal(out, expected[s.size:]) {
+		t.Errorf("clone after Read: got %x, expected %x", out, expected[s.size:])
+	}
+
+	if out := h.Read(2 * s.size); !bytes.Equal(out, expected[s.size:]) {
+		t.Errorf("original after cloning: got %x, expected %x", out, expected[s.size:])
+	}
+}
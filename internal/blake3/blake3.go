@@ -0,0 +1,401 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+// Package blake3 is a pure Go implementation of the BLAKE3 hash function, supporting the regular hashing, keyed
+// hashing, and key derivation modes, with extendable output.
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+const (
+	// BlockSize is the size of a BLAKE3 block, in bytes.
+	BlockSize = 64
+
+	// ChunkSize is the size of a BLAKE3 chunk, i.e. a leaf of the tree, in bytes.
+	ChunkSize = 1024
+
+	// KeySize is the size of a BLAKE3 key, in bytes.
+	KeySize = 32
+
+	// Size is the default output size of BLAKE3, in bytes.
+	Size = 32
+
+	flagChunkStart        = 1 << 0
+	flagChunkEnd          = 1 << 1
+	flagParent            = 1 << 2
+	flagRoot              = 1 << 3
+	flagKeyedHash         = 1 << 4
+	flagDeriveKeyContext  = 1 << 5
+	flagDeriveKeyMaterial = 1 << 6
+
+	// maxDepth is the maximum depth of the tree, i.e. the size of the chaining value stack, for inputs up to 2^64 bytes.
+	maxDepth = 54
+)
+
+var (
+	// ErrKeySize indicates that the key is not KeySize bytes long.
+	ErrKeySize = errors.New("blake3: invalid key size")
+
+	errWriteAfterRead = errors.New("blake3: write after read")
+
+	iv = [8]uint32{
+		0x6A09E667, 0xBB67AE85, 0x3C6EF372, 0xA54FF53A, 0x510E527F, 0x9B05688C, 0x1F83D9AB, 0x5BE0CD19,
+	}
+
+	msgPermutation = [16]int{2, 6, 3, 10, 7, 0, 4, 13, 1, 11, 12, 5, 9, 14, 15, 8}
+)
+
+func g(state *[16]uint32, a, b, c, d int, mx, my uint32) {
+	state[a] = state[a] + state[b] + mx
+	state[d] = bits.RotateLeft32(state[d]^state[a], -16)
+	state[c] += state[d]
+	state[b] = bits.RotateLeft32(state[b]^state[c], -12)
+	state[a] = state[a] + state[b] + my
+	state[d] = bits.RotateLeft32(state[d]^state[a], -8)
+	state[c] += state[d]
+	state[b] = bits.RotateLeft32(state[b]^state[c], -7)
+}
+
+func round(state, m *[16]uint32) {
+	// Mix the columns.
+	g(state, 0, 4, 8, 12, m[0], m[1])
+	g(state, 1, 5, 9, 13, m[2], m[3])
+	g(state, 2, 6, 10, 14, m[4], m[5])
+	g(state, 3, 7, 11, 15, m[6], m[7])
+
+	// Mix the diagonals.
+	g(state, 0, 5, 10, 15, m[8], m[9])
+	g(state, 1, 6, 11, 12, m[10], m[11])
+	g(state, 2, 7, 8, 13, m[12], m[13])
+	g(state, 3, 4, 9, 14, m[14], m[15])
+}
+
+func permute(m *[16]uint32) {
+	var permuted [16]uint32
+	for i, j := range msgPermutation {
+		permuted[i] = m[j]
+	}
+
+	*m = permuted
+}
+
+func compress(cv *[8]uint32, block *[16]uint32, counter uint64, blockLen, flags uint32) [16]uint32 {
+	state := [16]uint32{
+		cv[0], cv[1], cv[2], cv[3], cv[4], cv[5], cv[6], cv[7],
+		iv[0], iv[1], iv[2], iv[3],
+		uint32(counter), uint32(counter >> 32), blockLen, flags,
+	}
+	m := *block
+
+	for i := 0; i < 7; i++ {
+		round(&state, &m)
+
+		if i < 6 {
+			permute(&m)
+		}
+	}
+
+	for i := 0; i < 8; i++ {
+		state[i] ^= state[i+8]
+		state[i+8] ^= cv[i]
+	}
+
+	return state
+}
+
+func first8(words [16]uint32) (cv [8]uint32) {
+	copy(cv[:], words[:8])
+	return cv
+}
+
+func wordsFromBytes(b *[BlockSize]byte) (words [16]uint32) {
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+
+	return words
+}
+
+func keyWords(key []byte) (words [8]uint32) {
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(key[4*i:])
+	}
+
+	return words
+}
+
+// output is the state just prior to the compression of a chunk's last block or of a parent node, from which either a
+// chaining value or, for the root node, any number of output bytes can be computed.
+type output struct {
+	inputCV  [8]uint32
+	block    [16]uint32
+	counter  uint64
+	blockLen uint32
+	flags    uint32
+}
+
+func (o output) chainingValue() [8]uint32 {
+	return first8(compress(&o.inputCV, &o.block, o.counter, o.blockLen, o.flags))
+}
+
+// rootBlock writes the 64 output bytes of the root node at the given output block counter into dst.
+func (o *output) rootBlock(dst *[BlockSize]byte, outputCounter uint64) {
+	words := compress(&o.inputCV, &o.block, outputCounter, o.blockLen, o.flags|flagRoot)
+	for i, w := range words {
+		binary.LittleEndian.PutUint32(dst[4*i:], w)
+	}
+}
+
+func parentOutput(left, right *[8]uint32, key *[8]uint32, flags uint32) output {
+	var block [16]uint32
+
+	copy(block[:8], left[:])
+	copy(block[8:], right[:])
+
+	return output{
+		inputCV:  *key,
+		block:    block,
+		counter:  0,
+		blockLen: BlockSize,
+		flags:    flags | flagParent,
+	}
+}
+
+func parentCV(left, right *[8]uint32, key *[8]uint32, flags uint32) [8]uint32 {
+	return parentOutput(left, right, key, flags).chainingValue()
+}
+
+type chunkState struct {
+	cv               [8]uint32
+	chunkCounter     uint64
+	block            [BlockSize]byte
+	blockLen         int
+	blocksCompressed int
+	flags            uint32
+}
+
+func newChunkState(key *[8]uint32, chunkCounter uint64, flags uint32) chunkState {
+	return chunkState{
+		cv:               *key,
+		chunkCounter:     chunkCounter,
+		block:            [BlockSize]byte{},
+		blockLen:         0,
+		blocksCompressed: 0,
+		flags:            flags,
+	}
+}
+
+func (c *chunkState) len() int {
+	return BlockSize*c.blocksCompressed + c.blockLen
+}
+
+func (c *chunkState) startFlag() uint32 {
+	if c.blocksCompressed == 0 {
+		return flagChunkStart
+	}
+
+	return 0
+}
+
+func (c *chunkState) update(input []byte) {
+	for len(input) > 0 {
+		if c.blockLen == BlockSize {
+			words := wordsFromBytes(&c.block)
+			c.cv = first8(compress(&c.cv, &words, c.chunkCounter, BlockSize, c.flags|c.startFlag()))
+			c.blocksCompressed++
+			c.block = [BlockSize]byte{}
+			c.blockLen = 0
+		}
+
+		n := copy(c.block[c.blockLen:], input)
+		c.blockLen += n
+		input = input[n:]
+	}
+}
+
+func (c *chunkState) output() output {
+	return output{
+		inputCV:  c.cv,
+		block:    wordsFromBytes(&c.block),
+		counter:  c.chunkCounter,
+		blockLen: uint32(c.blockLen),
+		flags:    c.flags | c.startFlag() | flagChunkEnd,
+	}
+}
+
+// Hasher is an incremental BLAKE3 hasher with extendable output. Once Read has been called, no more data can be
+// written until Reset is called.
+type Hasher struct {
+	key      [8]uint32
+	stack    [maxDepth][8]uint32
+	chunk    chunkState
+	root     output
+	outBlock [BlockSize]byte
+	outCount uint64
+	stackLen int
+	outOff   int
+	flags    uint32
+	reading  bool
+}
+
+func newHasher(key *[8]uint32, flags uint32) *Hasher {
+	h := &Hasher{key: *key, flags: flags}
+	h.Reset()
+
+	return h
+}
+
+// New returns a new Hasher in the regular hashing mode.
+func New() *Hasher {
+	return newHasher(&iv, 0)
+}
+
+// NewKeyed returns a new Hasher in the keyed hashing mode, which can be used as a MAC or PRF. It returns ErrKeySize if
+// the key is not KeySize bytes long.
+func NewKeyed(key []byte) (*Hasher, error) {
+	if len(key) != KeySize {
+		return nil, ErrKeySize
+	}
+
+	k := keyWords(key)
+
+	return newHasher(&k, flagKeyedHash), nil
+}
+
+// NewDeriveKey returns a new Hasher in the key derivation mode, for the given context string. The key material is then
+// written to the Hasher, and the derived key read from it. The context string should be hardcoded, globally unique,
+// and application-specific.
+func NewDeriveKey(context string) *Hasher {
+	contextHasher := newHasher(&iv, flagDeriveKeyContext)
+	_, _ = contextHasher.Write([]byte(context))
+
+	var contextKey [KeySize]byte
+
+	_, _ = contextHasher.Read(contextKey[:])
+	k := keyWords(contextKey[:])
+
+	return newHasher(&k, flagDeriveKeyMaterial)
+}
+
+// Reset resets the Hasher to its initial state, retaining its key and mode.
+func (h *Hasher) Reset() {
+	h.chunk = newChunkState(&h.key, 0, h.flags)
+	h.stackLen = 0
+	h.reading = false
+	h.root = output{}
+	h.outCount = 0
+	h.outOff = BlockSize
+}
+
+// Clone returns a copy of the Hasher in its current state, including its output position.
+func (h *Hasher) Clone() *Hasher {
+	c := *h
+
+	return &c
+}
+
+// Size returns the default output size, in bytes.
+func (h *Hasher) Size() int {
+	return Size
+}
+
+// BlockSize returns the BLAKE3 block size, in bytes.
+func (h *Hasher) BlockSize() int {
+	return BlockSize
+}
+
+func (h *Hasher) pushStack(cv *[8]uint32) {
+	h.stack[h.stackLen] = *cv
+	h.stackLen++
+}
+
+func (h *Hasher) popStack() [8]uint32 {
+	h.stackLen--
+	return h.stack[h.stackLen]
+}
+
+// addChainingValue adds the chaining value of a completed subtree to the stack, merging it with its completed
+// siblings. total is the number of subtrees of the same size, including this one, that have been completed so far.
+func (h *Hasher) addChainingValue(cv [8]uint32, total uint64) {
+	for total&1 == 0 {
+		left := h.popStack()
+		cv = parentCV(&left, &cv, &h.key, h.flags)
+		total >>= 1
+	}
+
+	h.pushStack(&cv)
+}
+
+// Write absorbs more input into the hash state. It never returns an error, but panics if called after Read. Large
+// inputs are hashed in parallel, using the tree structure of BLAKE3.
+func (h *Hasher) Write(input []byte) (int, error) {
+	if h.reading {
+		panic(errWriteAfterRead)
+	}
+
+	n := len(input)
+
+	for len(input) > 0 {
+		if h.chunk.len() == ChunkSize {
+			cv := h.chunk.output().chainingValue()
+			total := h.chunk.chunkCounter + 1
+			h.addChainingValue(cv, total)
+			h.chunk = newChunkState(&h.key, total, h.flags)
+		}
+
+		if h.chunk.len() == 0 {
+			input = h.writeSubtrees(input)
+		}
+
+		take := min(ChunkSize-h.chunk.len(), len(input))
+		h.chunk.update(input[:take])
+		input = input[take:]
+	}
+
+	return n, nil
+}
+
+// Read squeezes len(p) bytes of output from the hash. It never returns an error. Subsequent calls continue the output
+// stream.
+func (h *Hasher) Read(p []byte) (int, error) {
+	if !h.reading {
+		h.root = h.rootOutput()
+		h.reading = true
+	}
+
+	n := len(p)
+
+	for len(p) > 0 {
+		if h.outOff == BlockSize {
+			h.root.rootBlock(&h.outBlock, h.outCount)
+			h.outCount++
+			h.outOff = 0
+		}
+
+		c := copy(p, h.outBlock[h.outOff:])
+		h.outOff += c
+		p = p[c:]
+	}
+
+	return n, nil
+}
+
+func (h *Hasher) rootOutput() output {
+	out := h.chunk.output()
+
+	for i := h.stackLen - 1; i >= 0; i-- {
+		cv := out.chainingValue()
+		out = parentOutput(&h.stack[i], &cv, &h.key, h.flags)
+	}
+
+	return out
+}
@@ -0,0 +1,182 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package blake3
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+const (
+	magic = "b3\x01"
+
+	// marshaledHeaderSize is the size of the serialized magic, key, flags, and stack length.
+	marshaledHeaderSize = len(magic) + 4*8 + 4 + 1
+
+	// marshaledFixedSize is the size of the serialized state without the chaining value stack.
+	marshaledFixedSize = marshaledHeaderSize +
+		4*8 + 8 + BlockSize + 1 + 1 + 4 + // chunk state
+		1 + 8 + 1 + BlockSize // output state
+)
+
+var (
+	errMarshalKeyed = errors.New("blake3: cannot marshal keyed hash state")
+	errInvalidState = errors.New("blake3: invalid hash state")
+)
+
+func appendWords(b []byte, words *[8]uint32) []byte {
+	for _, w := range words {
+		b = binary.LittleEndian.AppendUint32(b, w)
+	}
+
+	return b
+}
+
+func consumeWords(b []byte, words *[8]uint32) []byte {
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint32(b[4*i:])
+	}
+
+	return b[4*8:]
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The state of keyed hashers is not serialized, since it contains
+// the key.
+func (h *Hasher) MarshalBinary() ([]byte, error) {
+	if h.flags&flagKeyedHash != 0 {
+		return nil, errMarshalKeyed
+	}
+
+	b := make([]byte, 0, marshaledFixedSize+h.stackLen*4*8)
+	b = append(b, magic...)
+	b = appendWords(b, &h.key)
+	b = binary.LittleEndian.AppendUint32(b, h.flags)
+	b = append(b, byte(h.stackLen))
+
+	for i := range h.stackLen {
+		b = appendWords(b, &h.stack[i])
+	}
+
+	b = appendWords(b, &h.chunk.cv)
+	b = binary.LittleEndian.AppendUint64(b, h.chunk.chunkCounter)
+	b = append(b, h.chunk.block[:]...)
+	b = append(b, byte(h.chunk.blockLen), byte(h.chunk.blocksCompressed))
+	b = binary.LittleEndian.AppendUint32(b, h.chunk.flags)
+
+	b = append(b, boolByte(h.reading))
+	b = binary.LittleEndian.AppendUint64(b, h.outCount)
+	b = append(b, byte(h.outOff))
+	b = append(b, h.outBlock[:]...)
+
+	return b, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and restores a state serialized by MarshalBinary.
+func (h *Hasher) UnmarshalBinary(b []byte) error {
+	if len(b) < marshaledFixedSize || string(b[:len(magic)]) != magic {
+		return errInvalidState
+	}
+
+	var s Hasher
+
+	b = consumeWords(b[len(magic):], &s.key)
+	s.flags = binary.LittleEndian.Uint32(b)
+	s.stackLen = int(b[4])
+	b = b[5:]
+
+	if s.flags != 0 && s.flags != flagDeriveKeyMaterial ||
+		s.stackLen > maxDepth || len(b) != marshaledFixedSize-marshaledHeaderSize+s.stackLen*4*8 {
+		return errInvalidState
+	}
+
+	for i := range s.stackLen {
+		b = consumeWords(b, &s.stack[i])
+	}
+
+	b = consumeWords(b, &s.chunk.cv)
+	s.chunk.chunkCounter = binary.LittleEndian.Uint64(b)
+	b = b[8+copy(s.chunk.block[:], b[8:]):]
+	s.chunk.blockLen, s.chunk.blocksCompressed = int(b[0]), int(b[1])
+	s.chunk.flags = binary.LittleEndian.Uint32(b[2:])
+	b = b[6:]
+
+	s.reading = b[0] == 1
+	s.outCount = binary.LittleEndian.Uint64(b[1:])
+	s.outOff = int(b[9])
+	copy(s.outBlock[:], b[10:])
+
+	if b[0] > 1 || !s.consistent() {
+		return errInvalidState
+	}
+
+	if s.reading {
+		s.root = s.rootOutput()
+
+		var block [BlockSize]byte
+		if s.outCount != 0 {
+			s.root.rootBlock(&block, s.outCount-1)
+		}
+
+		if s.outCount != 0 && block != s.outBlock {
+			return errInvalidState
+		}
+	}
+
+	*h = s
+
+	return nil
+}
+
+// consistent reports whether the restored state could have been reached by writing to and reading from a Hasher, so
+// that subsequent calls can neither panic nor silently diverge from the output of the original state.
+func (h *Hasher) consistent() bool {
+	c := &h.chunk
+
+	// The stack holds one chaining value per complete subtree, i.e. per bit set in the number of completed chunks.
+	if h.stackLen != bits.OnesCount64(c.chunkCounter) {
+		return false
+	}
+
+	// Only the regular hashing mode uses the IV as key.
+	if h.flags == 0 && h.key != iv || c.flags != h.flags {
+		return false
+	}
+
+	// A block is only compressed once more input follows it, so the last block of a chunk is always buffered.
+	if c.blockLen > BlockSize || c.blocksCompressed >= ChunkSize/BlockSize ||
+		c.blocksCompressed != 0 && c.blockLen == 0 || c.blocksCompressed == 0 && c.cv != h.key {
+		return false
+	}
+
+	for _, v := range c.block[c.blockLen:] {
+		if v != 0 {
+			return false
+		}
+	}
+
+	// Output blocks are only computed when reading, and a fresh block is always at least partially consumed.
+	if !h.reading {
+		return h.outCount == 0 && h.outOff == BlockSize
+	}
+
+	if h.outCount == 0 {
+		return h.outOff == BlockSize
+	}
+
+	return h.outOff > 0 && h.outOff <= BlockSize
+}
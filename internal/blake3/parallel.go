@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package blake3
+
+import (
+	"runtime"
+	"sync"
+)
+
+const (
+	// parallelMinChunks is the minimum number of chunks in a subtree for it to be hashed in parallel.
+	parallelMinChunks = 16
+
+	// parallelMaxChunks is the maximum number of chunks in a subtree hashed in parallel, bounding the memory used.
+	parallelMaxChunks = 1 << 12
+)
+
+// writeSubtrees hashes the largest complete subtrees at the beginning of input in parallel, adds their chaining values
+// to the stack, and returns the remaining input. It must only be called on a chunk boundary.
+func (h *Hasher) writeSubtrees(input []byte) []byte {
+	for {
+		counter := h.chunk.chunkCounter
+
+		n := subtreeChunks(counter, len(input))
+		if n < parallelMinChunks {
+			return input
+		}
+
+		cv := h.subtreeChainingValue(input[:n*ChunkSize], counter)
+		h.addChainingValue(cv, (counter+n)/n)
+		h.chunk = newChunkState(&h.key, counter+n, h.flags)
+		input = input[n*ChunkSize:]
+	}
+}
+
+// subtreeChunks returns the largest power of two number of chunks, at most parallelMaxChunks, that is aligned with the
+// chunk counter and fits strictly within length bytes. The subtree is then never the root of the tree, which must be
+// finalized differently.
+func subtreeChunks(counter uint64, length int) uint64 {
+	n := uint64(parallelMaxChunks)
+	for n > 0 && (counter%n != 0 || n*ChunkSize >= uint64(length)) {
+		n >>= 1
+	}
+
+	return n
+}
+
+// subtreeChainingValue returns the chaining value of the complete subtree of input, whose first chunk has the given
+// counter. The chunks are hashed concurrently, and then merged up to the subtree's root.
+func (h *Hasher) subtreeChainingValue(input []byte, counter uint64) [8]uint32 {
+	n := len(input) / ChunkSize
+	cvs := make([][8]uint32, n)
+	perWorker := (n + runtime.GOMAXPROCS(0) - 1) / runtime.GOMAXPROCS(0)
+
+	var wg sync.WaitGroup
+
+	for start := 0; start < n; start += perWorker {
+		end := min(start+perWorker, n)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := start; i < end; i++ {
+				c := newChunkState(&h.key, counter+uint64(i), h.flags)
+				c.update(input[i*ChunkSize : (i+1)*ChunkSize])
+				cvs[i] = c.output().chainingValue()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for len(cvs) > 1 {
+		for i := 0; i < len(cvs)/2; i++ {
+			cvs[i] = parentCV(&cvs[2*i], &cvs[2*i+1], &h.key, h.flags)
+		}
+
+		cvs = cvs[:len(cvs)/2]
+	}
+
+	return cvs[0]
+}
@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+// Package merkle implements append-only Merkle trees as specified in RFC 6962 and RFC 9162 for Certificate
+// Transparency, with inclusion and consistency proofs, over any hash function of github.com/bytemare/hash. Leaves and
+// interior nodes are domain separated with the 0x00 and 0x01 prefixes, respectively.
+package merkle
+
+import (
+	"bytes"
+	"errors"
+	"math/bits"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+var (
+	// ErrInvalidIndex indicates that the leaf index is not in the tree.
+	ErrInvalidIndex = errors.New("leaf index out of range")
+
+	// ErrInvalidSize indicates that the tree size is 0 when it must not be, larger than the current tree, or that the
+	// sizes of a consistency proof are not ordered.
+	ErrInvalidSize = errors.New("invalid tree size")
+
+	// ErrInvalidProof indicates that the proof verification failed.
+	ErrInvalidProof = errors.New("invalid proof")
+)
+
+// hasher computes the leaf and interior node hashes with a standard output size.
+type hasher struct {
+	hash.Hasher
+	size uint
+}
+
+func newHasher(h hash.Hash) (*hasher, error) {
+	hh, err := h.NewE()
+	if err != nil {
+		return nil, err
+	}
+
+	return &hasher{Hasher: hh, size: uint(h.Size())}, nil
+}
+
+func (h *hasher) leaf(data []byte) []byte {
+	return h.Hash(h.size, []byte{leafPrefix}, data)
+}
+
+func (h *hasher) node(left, right []byte) []byte {
+	return h.Hash(h.size, []byte{nodePrefix}, left, right)
+}
+
+// LeafHash returns the hash of the leaf data, i.e. HASH(0x00 || data), as used in the tree and in proofs. It returns
+// hash.ErrUnavailable if the hash function is not available.
+func LeafHash(h hash.Hash, data []byte) ([]byte, error) {
+	hh, err := newHasher(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return hh.leaf(data), nil
+}
+
+// Tree is an append-only Merkle tree. It retains the hashes of all complete subtrees, such that roots, including
+// those of previous tree sizes, and proofs are computed in logarithmic time. A Tree is not safe for concurrent use.
+type Tree struct {
+	hasher *hasher
+
+	// levels[l] holds the roots of the complete subtrees of 2^l leaves, in order, such that levels[0] holds the leaf
+	// hashes.
+	levels [][][]byte
+}
+
+// New returns an empty Tree using the hash function h, or hash.ErrUnavailable if it is not available.
+func New(h hash.Hash) (*Tree, error) {
+	hh, err := newHasher(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tree{
+		hasher: hh,
+		levels: [][][]byte{nil},
+	}, nil
+}
+
+// Algorithm returns the hash function of the tree.
+func (t *Tree) Algorithm() hash.Hash {
+	return t.hasher.Algorithm()
+}
+
+// Size returns the number of leaves in the tree.
+func (t *Tree) Size() uint64 {
+	return uint64(len(t.levels[0]))
+}
+
+// Append adds a leaf with the given data to the tree, and returns its index.
+func (t *Tree) Append(data []byte) uint64 {
+	index := t.Size()
+	node := t.hasher.leaf(data)
+	t.levels[0] = append(t.levels[0], node)
+
+	// Each complete subtree ending at the new leaf gets a new root.
+	for level, i := 0, index; i&1 == 1; level, i = level+1, i>>1 {
+		node = t.hasher.node(t.levels[level][i-1], node)
+
+		if level+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+
+		t.levels[level+1] = append(t.levels[level+1], node)
+	}
+
+	return index
+}
+
+// split returns the largest power of 2 smaller than n, for n > 1.
+func split(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// subtreeRoot returns MTH(D[start:end]), for start < end.
+func (t *Tree) subtreeRoot(start, end uint64) []byte {
+	n := end - start
+	if n&(n-1) == 0 && start%n == 0 {
+		level := bits.TrailingZeros64(n)
+		return t.levels[level][start>>level]
+	}
+
+	k := split(n)
+
+	return t.hasher.node(t.subtreeRoot(start, start+k), t.subtreeRoot(start+k, end))
+}
+
+// Root returns the root hash of the current tree. The root of the empty tree is the hash of the empty string.
+func (t *Tree) Root() []byte {
+	root, _ := t.RootAt(t.Size())
+	return root
+}
+
+// RootAt returns the root hash of the tree when it had size leaves, or ErrInvalidSize if size is larger than the
+// current tree.
+func (t *Tree) RootAt(size uint64) ([]byte, error) {
+	if size > t.Size() {
+		return nil, ErrInvalidSize
+	}
+
+	if size == 0 {
+		return t.hasher.Hash(t.hasher.size), nil
+	}
+
+	return t.subtreeRoot(0, size), nil
+}
+
+// InclusionProof returns the audit path of the leaf at index in the tree of size leaves, as defined by PATH in
+// RFC 6962. It returns ErrInvalidSize if size is larger than the current tree, and ErrInvalidIndex if index is not
+// smaller than size.
+func (t *Tree) InclusionProof(index, size uint64) ([][]byte, error) {
+	if size > t.Size() {
+		return nil, ErrInvalidSize
+	}
+
+	if index >= size {
+		return nil, ErrInvalidIndex
+	}
+
+	return t.path(index, 0, size), nil
+}
+
+// path returns PATH(m, D[start:end]), for the leaf index m relative to start.
+func (t *Tree) path(m, start, end uint64) [][]byte {
+	n := end - start
+	if n == 1 {
+		return nil
+	}
+
+	k := split(n)
+	if m < k {
+		return append(t.path(m, start, start+k), t.subtreeRoot(start+k, end))
+	}
+
+	return append(t.path(m-k, start+k, end), t.subtreeRoot(start, start+k))
+}
+
+// ConsistencyProof returns the proof that the tree of oldSize leaves is a prefix of the tree of newSize leaves, as
+// defined by PROOF in RFC 6962. It returns ErrInvalidSize if oldSize is 0 or larger than newSize, or if newSize is
+// larger than the current tree.
+func (t *Tree) ConsistencyProof(oldSize, newSize uint64) ([][]byte, error) {
+	if oldSize == 0 || oldSize > newSize || newSize > t.Size() {
+		return nil, ErrInvalidSize
+	}
+
+	return t.subproof(oldSize, 0, newSize, true), nil
+}
+
+// subproof returns SUBPROOF(m, D[start:end], b).
+func (t *Tree) subproof(m, start, end uint64, b bool) [][]byte {
+	n := end - start
+	if m == n {
+		if b {
+			return nil
+		}
+
+		return [][]byte{t.subtreeRoot(start, end)}
+	}
+
+	k := split(n)
+	if m <= k {
+		return append(t.subproof(m, start, start+k, b), t.subtreeRoot(start+k, end))
+	}
+
+	return append(t.subproof(m-k, start+k, end, false), t.subtreeRoot(start, start+k))
+}
+
+// VerifyInclusion verifies, as specified in RFC 9162, that the leaf hash at index is included in the tree of size
+// leaves with the given root, using the inclusion proof. It returns ErrInvalidIndex if index is not smaller than size,
+// hash.ErrUnavailable if h is not available, and ErrInvalidProof if the verification fails.
+func VerifyInclusion(h hash.Hash, index, size uint64, leafHash []byte, proof [][]byte, root []byte) error {
+	if index >= size {
+		return ErrInvalidIndex
+	}
+
+	hh, err := newHasher(h)
+	if err != nil {
+		return err
+	}
+
+	fn, sn := index, size-1
+	r := leafHash
+
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			r = hh.node(p, r)
+
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = hh.node(r, p)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(r, root) {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
+
+// VerifyConsistency verifies, as specified in RFC 9162, that the tree of oldSize leaves with oldRoot is a prefix of
+// the tree of newSize leaves with newRoot, using the consistency proof. It returns ErrInvalidSize if oldSize is 0 or
+// larger than newSize, hash.ErrUnavailable if h is not available, and ErrInvalidProof if the verification fails.
+func VerifyConsistency(h hash.Hash, oldSize, newSize uint64, oldRoot, newRoot []byte, proof [][]byte) error {
+	if oldSize == 0 || oldSize > newSize {
+		return ErrInvalidSize
+	}
+
+	hh, err := newHasher(h)
+	if err != nil {
+		return err
+	}
+
+	if oldSize == newSize {
+		if len(proof) != 0 || !bytes.Equal(oldRoot, newRoot) {
+			return ErrInvalidProof
+		}
+
+		return nil
+	}
+
+	if len(proof) == 0 {
+		return ErrInvalidProof
+	}
+
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([][]byte{oldRoot}, proof...)
+	}
+
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+
+		if fn&1 == 1 || fn == sn {
+			fr = hh.node(c, fr)
+			sr = hh.node(c, sr)
+
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = hh.node(sr, c)
+		}
+
+		fn >>= 1
+		sn >>= 1
+	}
+
+	if sn != 0 || !bytes.Equal(fr, oldRoot) || !bytes.Equal(sr, newRoot) {
+		return ErrInvalidProof
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import "sync"
+
+// parallelThreshold is the minimum write size in bytes from which a parallel MultiHasher hashes in goroutines, below
+// which their overhead outweighs the gain.
+const parallelThreshold = 4096
+
+// MultiHasher computes the digests of several hash functions over the same input in a single pass. It implements
+// io.Writer, such that it can be used with io.Copy or io.TeeReader. A MultiHasher is not safe for concurrent use.
+type MultiHasher struct {
+	hashers  []Hasher
+	parallel bool
+}
+
+// NewMultiHasher returns a MultiHasher of the hash functions, which writes the input to each of them in turn. Repeated
+// functions are ignored. It returns ErrUnavailable if one of them is not available.
+func NewMultiHasher(hashes ...Hash) (*MultiHasher, error) {
+	m := &MultiHasher{hashers: make([]Hasher, 0, len(hashes))}
+	seen := make(map[Hash]bool, len(hashes))
+
+	for _, h := range hashes {
+		if seen[h] {
+			continue
+		}
+
+		hasher, err := h.NewE()
+		if err != nil {
+			return nil, err
+		}
+
+		seen[h] = true
+		m.hashers = append(m.hashers, hasher)
+	}
+
+	return m, nil
+}
+
+// NewParallelMultiHasher is like NewMultiHasher, but the returned MultiHasher hashes each large write with all
+// functions in parallel, using one goroutine per function. This speeds up hashing large inputs on multicore machines.
+func NewParallelMultiHasher(hashes ...Hash) (*MultiHasher, error) {
+	m, err := NewMultiHasher(hashes...)
+	if err != nil {
+		return nil, err
+	}
+
+	m.parallel = true
+
+	return m, nil
+}
+
+// Hashes returns the hash functions of the MultiHasher, in the order they were given.
+func (m *MultiHasher) Hashes() []Hash {
+	hashes := make([]Hash, len(m.hashers))
+	for i, hasher := range m.hashers {
+		hashes[i] = hasher.Algorithm()
+	}
+
+	return hashes
+}
+
+// Write implements io.Writer, and adds the input to all running hashes. It never returns an error.
+func (m *MultiHasher) Write(input []byte) (int, error) {
+	if !m.parallel || len(m.hashers) < 2 || len(input) < parallelThreshold {
+		for _, hasher := range m.hashers {
+			_, _ = hasher.Write(input)
+		}
+
+		return len(input), nil
+	}
+
+	var wg sync.WaitGroup
+
+	wg.Add(len(m.hashers))
+
+	for _, hasher := range m.hashers {
+		go func() {
+			defer wg.Done()
+
+			_, _ = hasher.Write(input)
+		}()
+	}
+
+	wg.Wait()
+
+	return len(input), nil
+}
+
+// Sums returns the standard size digests of the input written so far, by hash function. It does not change the
+// running hashes, such that more input can be written afterwards, except for extendable output functions registered
+// with a state that can't be cloned, from which the output is consumed.
+func (m *MultiHasher) Sums() map[Hash][]byte {
+	sums := make(map[Hash][]byte, len(m.hashers))
+
+	for _, hasher := range m.hashers {
+		// Reading from a copy preserves the output state of XOFs.
+		if xof := hasher.GetXOF(); xof != nil {
+			if c, err := xof.CloneE(); err == nil {
+				sums[hasher.Algorithm()] = c.Sum(nil)
+				continue
+			}
+		}
+
+		sums[hasher.Algorithm()] = hasher.Sum(nil)
+	}
+
+	return sums
+}
+
+// Reset resets all hashes to their initial state.
+func (m *MultiHasher) Reset() {
+	for _, hasher := range m.hashers {
+		hasher.Reset()
+	}
+}
@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+// Package multihash implements the multihash self-describing digest format, as used by IPFS and libp2p, over the hash
+// functions of github.com/bytemare/hash. A multihash is the concatenation of the unsigned varint encoding of the hash
+// function's multicodec code, the unsigned varint encoding of the digest length, and the digest.
+package multihash
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/bytemare/hash"
+)
+
+// maxVarintLength is the maximum length of an unsigned varint in the multiformats specification.
+const maxVarintLength = 9
+
+var (
+	// ErrNoCode indicates that the hash function has no multicodec code.
+	ErrNoCode = errors.New("hash function has no multicodec code")
+
+	// ErrUnknownCode indicates that the multicodec code is not associated to an available hash function.
+	ErrUnknownCode = errors.New("unknown multicodec code")
+
+	// ErrDuplicateCode indicates that the multicodec code or the hash function already has an association.
+	ErrDuplicateCode = errors.New("multicodec code or hash function already registered")
+
+	// ErrInvalidMultihash indicates that the multihash is malformed.
+	ErrInvalidMultihash = errors.New("invalid multihash")
+
+	// ErrInvalidLength indicates that the digest length is invalid for the hash function, i.e. 0, or larger than the
+	// output size of a fixed output length function.
+	ErrInvalidLength = errors.New("invalid multihash digest length")
+
+	// ErrMismatch indicates that the data does not match the multihash.
+	ErrMismatch = errors.New("data does not match the multihash")
+)
+
+// Multicodec codes of the built-in hash functions, from the multiformats table.
+const (
+	SHA2_256     uint64 = 0x12
+	SHA2_512     uint64 = 0x13
+	SHA3_512     uint64 = 0x14
+	SHA3_384     uint64 = 0x15
+	SHA3_256     uint64 = 0x16
+	SHA3_224     uint64 = 0x17
+	SHAKE128     uint64 = 0x18
+	SHAKE256     uint64 = 0x19
+	SHA2_384     uint64 = 0x20
+	BLAKE3       uint64 = 0x1e
+	SHA2_224     uint64 = 0x1013
+	SHA2_512_224 uint64 = 0x1014
+	SHA2_512_256 uint64 = 0x1015
+	BLAKE2B_256  uint64 = 0xb220
+	BLAKE2B_384  uint64 = 0xb230
+	BLAKE2B_512  uint64 = 0xb240
+	BLAKE2S_256  uint64 = 0xb260
+)
+
+var (
+	registryLock sync.Mutex
+
+	codes = map[hash.Hash]uint64{
+		hash.SHA224:      SHA2_224,
+		hash.SHA256:      SHA2_256,
+		hash.SHA384:      SHA2_384,
+		hash.SHA512:      SHA2_512,
+		hash.SHA512_224:  SHA2_512_224,
+		hash.SHA512_256:  SHA2_512_256,
+		hash.SHA3_224:    SHA3_224,
+		hash.SHA3_256:    SHA3_256,
+		hash.SHA3_384:    SHA3_384,
+		hash.SHA3_512:    SHA3_512,
+		hash.SHAKE128:    SHAKE128,
+		hash.SHAKE256:    SHAKE256,
+		hash.BLAKE2B_256: BLAKE2B_256,
+		hash.BLAKE2B_384: BLAKE2B_384,
+		hash.BLAKE2B_512: BLAKE2B_512,
+		hash.BLAKE2S_256: BLAKE2S_256,
+		hash.BLAKE3:      BLAKE3,
+	}
+
+	hashes = reverse(codes)
+)
+
+func reverse(m map[hash.Hash]uint64) map[uint64]hash.Hash {
+	r := make(map[uint64]hash.Hash, len(m))
+	for h, c := range m {
+		r[c] = h
+	}
+
+	return r
+}
+
+// Register associates the multicodec code to the hash function, e.g. one registered with hash.Register. It returns
+// hash.ErrUnavailable if the function is not available, and ErrDuplicateCode if the code or the function already have
+// an association.
+//
+// Register is meant to be called during program initialization, before any concurrent use of this package.
+func Register(h hash.Hash, code uint64) error {
+	if !h.Available() {
+		return hash.ErrUnavailable
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := codes[h]; ok {
+		return ErrDuplicateCode
+	}
+
+	if _, ok := hashes[code]; ok {
+		return ErrDuplicateCode
+	}
+
+	codes[h] = code
+	hashes[code] = h
+
+	return nil
+}
+
+// Code returns the multicodec code of the hash function, or ErrNoCode if it has none.
+func Code(h hash.Hash) (uint64, error) {
+	code, ok := codes[h]
+	if !ok || !h.Available() {
+		return 0, ErrNoCode
+	}
+
+	return code, nil
+}
+
+// FromCode returns the hash function of the multicodec code, or ErrUnknownCode if there is none.
+func FromCode(code uint64) (hash.Hash, error) {
+	h, ok := hashes[code]
+	if !ok || !h.Available() {
+		return 0, ErrUnknownCode
+	}
+
+	return h, nil
+}
+
+// checkLength returns ErrInvalidLength if the digest length is 0, or larger than the output size of a fixed output
+// length function. Truncated digests are allowed, and XOFs accept any length.
+func checkLength(h hash.Hash, length uint64) error {
+	if length == 0 || (h.Type() == hash.FixedOutputLength && length > uint64(h.Size())) {
+		return ErrInvalidLength
+	}
+
+	return nil
+}
+
+// Encode returns the multihash of the digest produced by the hash function h. It returns ErrNoCode if h has no
+// multicodec code, and ErrInvalidLength if the digest length is invalid for h.
+func Encode(h hash.Hash, digest []byte) ([]byte, error) {
+	code, err := Code(h)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLength(h, uint64(len(digest))); err != nil {
+		return nil, err
+	}
+
+	mh := make([]byte, 0, 2*maxVarintLength+len(digest))
+	mh = binary.AppendUvarint(mh, code)
+	mh = binary.AppendUvarint(mh, uint64(len(digest)))
+
+	return append(mh, digest...), nil
+}
+
+// digest returns the first length bytes of the output of h over data.
+func digest(h hash.Hash, data []byte, length int) []byte {
+	hasher := h.New()
+	_, _ = hasher.Write(data)
+
+	return hasher.Read(max(length, h.Size()))[:length]
+}
+
+// Sum hashes data with h and returns its multihash with a digest of length bytes, or the function's output size if
+// length is 0. Lengths smaller than the output size truncate the digest, and XOFs accept larger lengths. It returns
+// ErrNoCode if h has no multicodec code, and ErrInvalidLength if the length is invalid for h.
+func Sum(h hash.Hash, data []byte, length int) ([]byte, error) {
+	if length < 0 {
+		return nil, ErrInvalidLength
+	}
+
+	if length == 0 {
+		length = h.Size()
+	}
+
+	if _, err := Code(h); err != nil {
+		return nil, err
+	}
+
+	if err := checkLength(h, uint64(length)); err != nil {
+		return nil, err
+	}
+
+	return Encode(h, digest(h, data, length))
+}
+
+// Decoded is a decoded multihash.
+type Decoded struct {
+	// Digest is the, possibly truncated, output of the hash function.
+	Digest []byte
+
+	// Code is the multicodec code of the hash function.
+	Code uint64
+
+	// Hash identifies the hash function.
+	Hash hash.Hash
+}
+
+// uvarint decodes a minimally encoded unsigned varint of at most maxVarintLength bytes from the beginning of b, and
+// returns it with the remainder of b.
+func uvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 || n > maxVarintLength || (n > 1 && b[n-1] == 0) {
+		return 0, nil, ErrInvalidMultihash
+	}
+
+	return v, b[n:], nil
+}
+
+// Decode decodes and validates the multihash. It returns ErrInvalidMultihash if it is malformed, ErrUnknownCode if
+// its hash function is unknown, and ErrInvalidLength if its digest length is invalid for the hash function. The
+// returned digest is a copy.
+func Decode(mh []byte) (*Decoded, error) {
+	code, rest, err := uvarint(mh)
+	if err != nil {
+		return nil, err
+	}
+
+	length, rest, err := uvarint(rest)
+	if err != nil {
+		return nil, err
+	}
+
+	if length != uint64(len(rest)) {
+		return nil, ErrInvalidMultihash
+	}
+
+	h, err := FromCode(code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = checkLength(h, length); err != nil {
+		return nil, err
+	}
+
+	return &Decoded{
+		Digest: append([]byte(nil), rest...),
+		Code:   code,
+		Hash:   h,
+	}, nil
+}
+
+// Verify returns nil if the multihash is valid and is the multihash of data, and ErrMismatch if it is valid but is not.
+// It returns the errors of Decode otherwise. The digests are compared in constant time.
+func Verify(mh, data []byte) error {
+	d, err := Decode(mh)
+	if err != nil {
+		return err
+	}
+
+	if subtle.ConstantTimeCompare(d.Digest, digest(d.Hash, data, len(d.Digest))) != 1 {
+		return ErrMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import "sync"
+
+// pools holds reusable Hasher instances of each registered hash function, such that one-shot hashing doesn't allocate
+// a new state for every call. Pooled Hashers are in their initial state, and only standard instances are pooled.
+var pools [maxRegistry]sync.Pool
+
+// setPool sets the constructor of the pooled Hashers of h.
+func setPool(h Hash, constructor newHash) {
+	pools[h].New = func() any {
+		return constructor()
+	}
+}
+
+// get returns a Hasher of h from the pool, which must be returned with put.
+func (h Hash) get() Hasher {
+	hasher, _ := pools[h].Get().(Hasher)
+	return hasher
+}
+
+// put resets the Hasher and returns it to the pool of h.
+func (h Hash) put(hasher Hasher) {
+	hasher.Reset()
+	pools[h].Put(hasher)
+}
+
+// poolable reports whether the Hasher is a standard instance of h.
+func (h Hash) poolable(hasher Hasher) bool {
+	switch s := hasher.(type) {
+	case *Fixed:
+		return s.standard && s.id == h
+	case *ExtendableHash:
+		return s.standard && s.id == h
+	default:
+		return false
+	}
+}
+
+// Acquire returns a Hasher of the hash function in its initial state, reusing a released one when possible. Like
+// any Hasher, it is not safe for concurrent use, and should be handed back with Release once done. It panics if the
+// hash function is not available.
+func (h Hash) Acquire() Hasher {
+	hasher, err := h.AcquireE()
+	if err != nil {
+		panic(err)
+	}
+
+	return hasher
+}
+
+// AcquireE returns a Hasher of the hash function in its initial state, reusing a released one when possible, or
+// ErrUnavailable if the hash function is not available.
+func (h Hash) AcquireE() (Hasher, error) {
+	if !h.Available() {
+		return nil, ErrUnavailable
+	}
+
+	return h.get(), nil
+}
+
+// Release resets the Hasher and makes it available to subsequent calls to Acquire. The Hasher must not be used after
+// it is released. Hashers of another hash function, keyed or customized ones, e.g. from NewKeyed or NewCSHAKE, and
+// those not created by this package are reset but not retained, such that they never leak into other callers.
+func (h Hash) Release(hasher Hasher) {
+	if hasher == nil {
+		return
+	}
+
+	if !h.poolable(hasher) {
+		hasher.Reset()
+		return
+	}
+
+	h.put(hasher)
+}
+
+// HashInto writes the hash of the concatenated input into dst, and returns the written part of dst. Fixed output
+// length functions write their Size bytes at the beginning of dst, while extendable output functions fill dst. The
+// internal state is taken from a pool, such that no allocation is made when dst is large enough. It panics with
+// ErrUnavailable if the hash function is not available, and with ErrSmallOutputSize if dst is shorter than Size.
+func (h Hash) HashInto(dst []byte, input ...[]byte) []byte {
+	if !h.Available() {
+		panic(ErrUnavailable)
+	}
+
+	if len(dst) < h.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
+	hasher := h.get()
+	defer h.put(hasher)
+
+	// Writing the input here rather than in the Hasher's HashInto keeps the variadic slice from escaping through the
+	// interface call, which would allocate.
+	for _, i := range input {
+		_, _ = hasher.Write(i)
+	}
+
+	return sumInto(hasher, dst)
+}
+
+// sumInto writes the current hash of hasher into dst, and returns the written part of dst. Fixed and ExtendableHash
+// don't allocate, while other Hashers go through Read.
+func sumInto(hasher Hasher, dst []byte) []byte {
+	switch h := hasher.(type) {
+	case *Fixed:
+		return h.SumInto(dst)
+	case *ExtendableHash:
+		return h.SumInto(dst)
+	case *SafeHasher:
+		return h.SumInto(dst)
+	default:
+		if len(dst) < hasher.Size() {
+			panic(ErrSmallOutputSize)
+		}
+
+		// Fixed output length functions ignore the size, and return their Size bytes.
+		return dst[:copy(dst, hasher.Read(len(dst)))]
+	}
+}
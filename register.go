@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"errors"
+	"hash"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrInvalidRegistration indicates that the parameters given to Register are invalid.
+	ErrInvalidRegistration = errors.New("invalid hash function registration")
+
+	// ErrDuplicateName indicates that a hash function with the same name is already registered.
+	ErrDuplicateName = errors.New("a hash function with this name is already registered")
+
+	// ErrRegistryFull indicates that no more Hash identifiers can be allocated.
+	ErrRegistryFull = errors.New("no hash identifier left to allocate")
+)
+
+// Constructor is the set of hash function constructors that can be registered: a hash.Hash constructor for fixed
+// output length functions, or an XOF constructor for extendable output functions.
+type Constructor interface {
+	func() hash.Hash | func() XOF
+}
+
+var (
+	registryLock sync.Mutex
+	nextID       = int(maxID)
+)
+
+// Register makes a third-party hash function available under a newly allocated Hash identifier, which is returned.
+// The constructor must return a new, ready to use, instance of the function, and its kind must match hashType. name
+// must be unique among registered functions, and is compared case-insensitively. block is the underlying block size in
+// bytes (or 0 if not relevant), output the standard output size in bytes, and security the security level in bits.
+// The constructor is called once to check that the block size, and for fixed output length functions the output size,
+// match those of the instances it returns.
+//
+// Register is meant to be called during program initialization (e.g. in an init function), before any concurrent use
+// of the Hash identifiers.
+func Register[C Constructor](constructor C, name string, hashType Type, block, output, security int) (Hash, error) {
+	if err := validateRegistration(constructor, name, hashType, block, output, security); err != nil {
+		return 0, err
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	for id := 0; id < maxRegistry; id++ {
+		if registeredHashes[id] && strings.EqualFold(names[id], name) {
+			return 0, ErrDuplicateName
+		}
+	}
+
+	if nextID >= maxRegistry {
+		return 0, ErrRegistryFull
+	}
+
+	h := Hash(nextID)
+	nextID++
+
+	register(h, constructor, name, block, output, security)
+
+	return h, nil
+}
+
+func validateRegistration[C Constructor](
+	constructor C,
+	name string,
+	hashType Type,
+	block, output, security int,
+) error {
+	if name == "" || block < 0 || output <= 0 || security <= 0 {
+		return ErrInvalidRegistration
+	}
+
+	// The constructor is instantiated once, so that the declared sizes can't silently disagree with the function, e.g.
+	// with an output size that would make SumInto reallocate.
+	switch c := any(constructor).(type) {
+	case func() hash.Hash:
+		if c == nil || hashType != FixedOutputLength {
+			return ErrInvalidRegistration
+		}
+
+		if h := c(); h == nil || h.Size() != output || h.BlockSize() != block {
+			return ErrInvalidRegistration
+		}
+	case func() XOF:
+		if c == nil || hashType != ExtendableOutputFunction {
+			return ErrInvalidRegistration
+		}
+
+		x := c()
+		if x == nil {
+			return ErrInvalidRegistration
+		}
+
+		// The output size of an XOF is chosen by the caller, but its block size, if exposed, must match.
+		if b, ok := x.(interface{ BlockSize() int }); ok && b.BlockSize() != block {
+			return ErrInvalidRegistration
+		}
+	}
+
+	return nil
+}
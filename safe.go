@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import "sync"
+
+// SafeHasher wraps a Hasher with a mutex, such that it can be shared by multiple goroutines. Each method call is
+// atomic, but sequences of calls are not: goroutines writing to the same SafeHasher interleave their input, so
+// sequences that must not be interleaved should use the Hash method, or a Hasher of their own from Acquire.
+type SafeHasher struct {
+	hasher Hasher
+	mu     sync.Mutex
+}
+
+// NewSafeHasher returns a SafeHasher guarding hasher, which must not be used directly afterwards.
+func NewSafeHasher(hasher Hasher) *SafeHasher {
+	return &SafeHasher{hasher: hasher}
+}
+
+// NewSafe returns a SafeHasher of the hash function. It panics if the hash function is not available.
+func (h Hash) NewSafe() *SafeHasher {
+	return NewSafeHasher(h.New())
+}
+
+// Algorithm returns the Hash function identifier.
+func (s *SafeHasher) Algorithm() Hash {
+	return s.hasher.Algorithm()
+}
+
+// Hash hashes the concatenation of input and returns size bytes, atomically. The size is ignored for fixed output
+// length hashes as their output size is standard.
+func (s *SafeHasher) Hash(size uint, input ...[]byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hasher.Hash(size, input...)
+}
+
+// HashInto hashes the concatenation of input into dst atomically, and returns the written part of dst. Fixed output
+// length functions write their Size bytes, and extendable output functions fill dst. It panics with
+// ErrSmallOutputSize if dst is shorter than Size.
+func (s *SafeHasher) HashInto(dst []byte, input ...[]byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(dst) < s.hasher.Size() {
+		panic(ErrSmallOutputSize)
+	}
+
+	s.hasher.Reset()
+
+	for _, i := range input {
+		_, _ = s.hasher.Write(i)
+	}
+
+	return sumInto(s.hasher, dst)
+}
+
+// Read returns size bytes from the current hash.
+// The underlying hash state is not modified for Merkle–Damgård constructions, and size bytes will be consumed
+// for extendable output functions.
+func (s *SafeHasher) Read(size int) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hasher.Read(size)
+}
+
+// Write implements io.Writer.
+func (s *SafeHasher) Write(input []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hasher.Write(input)
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+func (s *SafeHasher) Sum(prefix []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.hasher.Sum(prefix)
+}
+
+// SumInto writes the current hash into dst, and returns the written part of dst.
+// It panics with ErrSmallOutputSize if dst is shorter than Size.
+func (s *SafeHasher) SumInto(dst []byte) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return sumInto(s.hasher, dst)
+}
+
+// Reset resets the hash to its initial state.
+func (s *SafeHasher) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hasher.Reset()
+}
+
+// Size returns the number of bytes Hash will return.
+func (s *SafeHasher) Size() int {
+	return s.hasher.Size()
+}
+
+// BlockSize returns the hash's underlying block size.
+func (s *SafeHasher) BlockSize() int {
+	return s.hasher.BlockSize()
+}
+
+// Clone returns a new SafeHasher guarding an independent copy of the Hasher in its current state. It panics with
+// ErrNotClonable if the underlying state can't be copied.
+func (s *SafeHasher) Clone() Hasher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return NewSafeHasher(s.hasher.Clone())
+}
+
+// GetHashFunction returns the underlying Fixed Hasher for FixedOutputLength functions, and nil otherwise. The
+// returned Hasher is not guarded by the mutex.
+func (s *SafeHasher) GetHashFunction() *Fixed {
+	return s.hasher.GetHashFunction()
+}
+
+// GetXOF returns the underlying ExtendableHash Hasher for ExtendableOutputFunction functions, and nil otherwise. The
+// returned Hasher is not guarded by the mutex.
+func (s *SafeHasher) GetXOF() *ExtendableHash {
+	return s.hasher.GetXOF()
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// ErrSelfTest indicates that a known-answer test of SelfTest failed.
+var ErrSelfTest = errors.New("hash function self-test failed")
+
+// Names of the known-answer tests.
+const (
+	katDigest          = "digest"
+	katStreamingDigest = "streaming digest"
+	katHMAC            = "HMAC"
+	katHKDF            = "HKDF"
+)
+
+const (
+	// katShortMessage is the input of the one-shot digest tests.
+	katShortMessage = "abc"
+
+	// katLongLength is the length of the input of the streaming digest tests, which spans multiple blocks, and
+	// multiple chunks for BLAKE3.
+	katLongLength = 1025
+
+	// HMAC inputs from RFC 4231 test case 2.
+	katHMACKey     = "Jefe"
+	katHMACMessage = "what do ya want for nothing?"
+
+	// HKDF output length from RFC 5869 test case 1.
+	katHKDFLength = 42
+)
+
+// knownAnswer holds the hex encoded expected outputs of a hash function.
+type knownAnswer struct {
+	short, long string
+	hmac, hkdf  string
+}
+
+// knownAnswers holds the expected outputs of the built-in hash functions, with their standard output size. The long
+// input is the sequence of bytes i mod 251, as in the BLAKE3 test vectors. HMAC and HKDF use the inputs of RFC 4231
+// test case 2 and RFC 5869 test case 1, respectively, and match their outputs for SHA-2.
+var knownAnswers = map[Hash]knownAnswer{
+	SHA224: {
+		short: "23097d223405d8228642a477bda255b32aadbce4bda0b3f7e36c9da7",
+		long:  "614b5e145a54576f471b21e207a4f91e70d6db7c668471da7f96eda3",
+		hmac:  "a30e01098bc6dbbf45690f3a7e9e6d0f8bbea2a39e6148008fd05e44",
+		hkdf:  "2f21cd7cbc818ca5c561b933728e2e08e154a87e1432399a820dee13aa222d0cee6152fa539ab70f8e80",
+	},
+	SHA256: {
+		short: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad",
+		long:  "bc0b6b10b89b9487a12fda2a8cc13194e7091c217aabf8b92846274026f4bcd0",
+		hmac:  "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843",
+		hkdf:  "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865",
+	},
+	SHA384: {
+		short: "cb00753f45a35e8bb5a03d699ac65007272c32ab0eded1631a8b605a43ff5bed8086072ba1e7cc2358baeca134c825a7",
+		long:  "fde60c0157845cf167a59164fd4d47085b967721548e1580428dd54161e2d359db5981f758998e8b0e933115cd7ce811",
+		hmac:  "af45d2e376484031617f78d2b58a6b1b9c7ef464f5a01b47e42ec3736322445e8e2240ca5e69e2c78b3239ecfab21649",
+		hkdf:  "9b5097a86038b805309076a44b3a9f38063e25b516dcbf369f394cfab43685f748b6457763e4f0204fc5",
+	},
+	SHA512: {
+		short: "ddaf35a193617abacc417349ae20413112e6fa4e89a97ea20a9eeee64b55d39a2192992a274fc1a836ba3c23a3feebbd454d4423643ce80e2a9ac94fa54ca49f",
+		long:  "1f0cb287c12671e2f498170ff2762886686ceb88b7d63f944708d3060752376ff38e4a88ab7ceb0bb437083e7f1d051049b8d94356e72e4d59adcc102f585ac0",
+		hmac:  "164b7a7bfcf819e2e395fbe73b56e0a387bd64222e831fd610270cd7ea2505549758bf75c05a994a6d034f65f8f0e6fdcaeab1a34d4a6b4b636e070a38bce737",
+		hkdf:  "832390086cda71fb47625bb5ceb168e4c8e26a1a16ed34d9fc7fe92c1481579338da362cb8d9f925d7cb",
+	},
+	SHA512_224: {
+		short: "4634270f707b6a54daae7530460842e20e37ed265ceee9a43e8924aa",
+		long:  "7ebb5055a99d2a3b8e528d01210cc6c35398e5bec07882d884b978ee",
+		hmac:  "4a530b31a79ebcce36916546317c45f247d83241dfb818fd37254bde",
+		hkdf:  "f8d956e152b0fba831bac400f1a5af54982b91db3d96ae21a75655eff1725f928e491c63f3aedb408296",
+	},
+	SHA512_256: {
+		short: "53048e2681941ef99b2e29b76b4c7dabe4c2d0c634fc6d46e0e2f13107e7af23",
+		long:  "55d00c70e6bed390e0b965e7a06a675062c4f057e6121eabbba4310dba0d6a27",
+		hmac:  "6df7b24630d5ccb2ee335407081a87188c221489768fa2020513b2d593359456",
+		hkdf:  "789a93e567a1861de449342b2d674c0df737fd8adce2a8e1843237c1938ac413044b496ce267a198ebe3",
+	},
+	SHA3_224: {
+		short: "e642824c3f8cf24ad09234ee7d3c766fc9a3a5168d0c94ad73b46fdf",
+		long:  "faa2566329eef816510312ec08e329cdc855e96a440e47e92da4cc92",
+		hmac:  "7fdb8dd88bd2f60d1b798634ad386811c2cfc85bfaf5d52bbace5e66",
+		hkdf:  "5058867fc7bdb118ce6a703add6edbf8e2ce21f5766cfc2e662e1a36ff6922fa96fc149517cf1e451fe6",
+	},
+	SHA3_256: {
+		short: "3a985da74fe225b2045c172d6bd390bd855f086e3e9d525b46bfe24511431532",
+		long:  "413cf357775aef534fcd49da91a30f7877b50bbd924a20649315a4827f79cac0",
+		hmac:  "c7d4072e788877ae3596bbb0da73b887c9171f93095b294ae857fbe2645e1ba5",
+		hkdf:  "0c5160501d65021deaf2c14f5abce04c5bd2635abceeba61c2edb6e8ed72674900557728f2c9f2c4c179",
+	},
+	SHA3_384: {
+		short: "ec01498288516fc926459f58e2c6ad8df9b473cb0fc08c2596da7cf0e49be4b298d88cea927ac7f539f1edf228376d25",
+		long:  "d00dfd2110cb761ca3f3f3195037e89b29660805e6ce37e9f3164a22517e1c1ac937adbd38c78ad568ada4340f039e58",
+		hmac:  "f1101f8cbf9766fd6764d2ed61903f21ca9b18f57cf3e1a23ca13508a93243ce48c045dc007f26a21b3f5e0e9df4c20a",
+		hkdf:  "138d8521e5a346a9cb770f762b9c04d9ca317409fb6a3ef9cb905228385589ae883bbe8b07b009f0e08b",
+	},
+	SHA3_512: {
+		short: "b751850b1a57168a5693cd924b6b096e08f621827444f70d884f5d0240d2712e10e116e9192af3c91a7ec57647e3934057340b4cf408d5a56592f8274eec53f0",
+		long:  "4d77323a341a3c8edd736ed718cb07deb66bcc5317dbc6f73da4c686dcec8440e414a2ed46ed5219cc226160b2416b276d9d9d95ad83c4c9e301397c89e37864",
+		hmac:  "5a4bfeab6166427c7a3647b747292b8384537cdb89afb3bf5665e4c5e709350b287baec921fd7ca0ee7a0c31d022a95e1fc92ba9d77df883960275beb4e62024",
+		hkdf:  "40e9f17e9bf2ef99425c2b23ccdf20a018ea5513f9ae68e1ea8c626deb57dfa4d56c27ccf2a2a24488a5",
+	},
+	BLAKE2B_256: {
+		short: "bddd813c634239723171ef3fee98579b94964e3bb1cb3e427262c8c068d52319",
+		long:  "533c8d76c0f61487431e7c31d15417c8b53887e4765b5597d0d03cb085014afb",
+		hmac:  "3cf096eeeb2202a250db168c4823a44ef4618ebabb225789386fed316131e3a0",
+		hkdf:  "0f52cd59eb2c67b71fbb5e8bf5502938bc2f1024fc93e24ceba66e7006a93d097e25972f610388d6495c",
+	},
+	BLAKE2B_384: {
+		short: "6f56a82c8e7ef526dfe182eb5212f7db9df1317e57815dbda46083fc30f54ee6c66ba83be64b302d7cba6ce15bb556f4",
+		long:  "d0d359b37ec961d6637f2541a3d42dc4d4ea08ac30a51b4aa48d5b2f0a47c18d6d138f5ea31802eb0eca5c40704a6632",
+		hmac:  "e87f61624bc6c1db706a1efcc98e7e98c0ab8fea0978dbd87e2852406fdd313c87968c5b825847f3c04d975b8b88598d",
+		hkdf:  "37766306802f9f7bee56aec0aff4ea574ca54ff1a1c81b031633967d0a078380d3504d1b0c0fed805887",
+	},
+	BLAKE2B_512: {
+		short: "ba80a53f981c4d0d6a2797b69f12f6e94c212f14685ac4b74b12bb6fdbffa2d17d87c5392aab792dc252d5de4533cc9518d38aa8dbf1925ab92386edd4009923",
+		long:  "7a9e5283a15d13b995755360fde4c65c2ae1bc0cf33e8db2ce8416e5d10697c73fc4b2622a29b938a1faec43d931b02e71ad8635e071265633643a9d9396ec28",
+		hmac:  "6ff884f8ddc2a6586b3c98a4cd6ebdf14ec10204b6710073eb5865ade37a2643b8807c1335d107ecdb9ffeaeb6828c4625ba172c66379efcd222c2de11727ab4",
+		hkdf:  "8815e1a85b5e90e6174323fdd180248887a7138af6dc5c8320fde21a60a078808267d6a41b6a938d7b30",
+	},
+	BLAKE2S_256: {
+		short: "508c5e8c327c14e2e1a72ba34eeb452f37458b209ed63a294d999b4c86675982",
+		long:  "9b4b1bfb89177545cc59b321be5403774c58f061db927f04d206116b8278d2b4",
+		hmac:  "90b6281e2f3038c9056af0b4a7e763cae6fe5d9eb4386a0ec95237890c104ff0",
+		hkdf:  "1472c31f2ff768c71b19f8803683ee3b13c1a5fb3ea59c0c3bf0d44a4a40dcd4329d9cd85bbe35a1b3e7",
+	},
+	SHAKE128: {
+		short: "5881092dd818bf5cf8a3ddb793fbcba74097d5c526a6d35f97b83351940f2cc8",
+		long:  "cade7de1dfa08a5be2566cf519bb8f96747dc86e937f27554bdb75a336c858f2",
+	},
+	SHAKE256: {
+		short: "483366601360a8771c6863080cc4114d8db44530f8f1e1ee4f94ea37e78b5739",
+		long:  "35c3d95330b0077c02f14876e922c64a447205bc68f447f62092f7369c4dd79a",
+	},
+	BLAKE2XB: {
+		short: "ae080c1efbcf7f60ed52a04161d02b7ee63bed362534f0661da02c6e40cd2089",
+		long:  "acf1ebebfafead233cd7b7993f880c085fda5dd54d3245f02f60aa200b61510a",
+	},
+	BLAKE2XS: {
+		short: "bf5c4f309fde8a62195bc8364ceea81e84eb9330579270c5737b9300085b6149",
+		long:  "4490277f6abf60a63e961779bb4f9955b10b87053ff0b32033a3aeb3da3f85c4",
+	},
+	BLAKE3: {
+		short: "6437b3ac38465133ffb63b75273a8db548c558465d79db03fd359c6cd5bd9d85",
+		long:  "d00278ae47eb27b34faecf67b4fe263f82d5412916c1ffd97c8cb7fb814b8444",
+	},
+}
+
+// SelfTestFailure describes a failed known-answer test.
+type SelfTestFailure struct {
+	// Test is the name of the test, i.e. "digest", "streaming digest", "HMAC", or "HKDF".
+	Test string
+
+	// Expected is the known answer.
+	Expected []byte
+
+	// Got is the output of the hash function.
+	Got []byte
+
+	// Hash identifies the hash function.
+	Hash Hash
+}
+
+// Error implements the error interface.
+func (f *SelfTestFailure) Error() string {
+	return fmt.Sprintf("%s %s: expected %x, got %x", f.Hash, f.Test, f.Expected, f.Got)
+}
+
+// SelfTestReport is the result of SelfTest.
+type SelfTestReport struct {
+	// Passed holds the hash functions that passed all their known-answer tests.
+	Passed []Hash
+
+	// Skipped holds the available hash functions without embedded known answers, i.e. those registered with Register.
+	Skipped []Hash
+
+	// Failures holds the failed known-answer tests.
+	Failures []*SelfTestFailure
+}
+
+// OK reports whether all known-answer tests passed.
+func (r *SelfTestReport) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Err returns nil if all known-answer tests passed, and otherwise an error wrapping ErrSelfTest and all failures.
+func (r *SelfTestReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+
+	errs := make([]error, 0, len(r.Failures)+1)
+	errs = append(errs, ErrSelfTest)
+
+	for _, f := range r.Failures {
+		errs = append(errs, f)
+	}
+
+	return errors.Join(errs...)
+}
+
+// SelfTest runs embedded known-answer tests for every available hash function, such that a corrupted build or a broken
+// assembly implementation is detected before use. Each function is tested with one-shot and streaming inputs, and
+// fixed output length functions are also tested with HMAC and HKDF. Functions registered with Register have no known
+// answers, and are reported as skipped. Building with the hashselftest tag runs SelfTest at initialization, and
+// panics if it fails.
+func SelfTest() *SelfTestReport {
+	r := &SelfTestReport{}
+
+	for id := range maxRegistry {
+		h := Hash(id)
+		if !h.Available() {
+			continue
+		}
+
+		kat, ok := knownAnswers[h]
+		if !ok {
+			r.Skipped = append(r.Skipped, h)
+			continue
+		}
+
+		if failures := kat.run(h); len(failures) != 0 {
+			r.Failures = append(r.Failures, failures...)
+			continue
+		}
+
+		r.Passed = append(r.Passed, h)
+	}
+
+	return r
+}
+
+func (k *knownAnswer) run(h Hash) []*SelfTestFailure {
+	var failures []*SelfTestFailure
+
+	check := func(test, answer string, got []byte) {
+		expected, _ := hex.DecodeString(answer)
+		if !bytes.Equal(got, expected) {
+			failures = append(failures, &SelfTestFailure{Test: test, Expected: expected, Got: got, Hash: h})
+		}
+	}
+
+	check(katDigest, k.short, h.Hash([]byte(katShortMessage)))
+	check(katStreamingDigest, k.long, streamKnownAnswer(h))
+
+	if fixed := h.GetHashFunction(); fixed != nil {
+		check(katHMAC, k.hmac, fixed.Hmac([]byte(katHMACMessage), []byte(katHMACKey)))
+
+		ikm := bytes.Repeat([]byte{0x0b}, 22)
+		salt := []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c}
+		info := []byte{0xf0, 0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8, 0xf9}
+		check(katHKDF, k.hkdf, fixed.HKDF(ikm, salt, info, katHKDFLength))
+	}
+
+	return failures
+}
+
+// streamKnownAnswer returns the digest of the long known-answer input, written in chunks of increasing sizes such
+// that block boundaries are crossed at different offsets.
+func streamKnownAnswer(h Hash) []byte {
+	input := make([]byte, katLongLength)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	hasher := h.New()
+	for i, n := 0, 1; i < len(input); i, n = i+n, n+1 {
+		_, _ = hasher.Write(input[i:min(i+n, len(input))])
+	}
+
+	return hasher.Sum(nil)
+}
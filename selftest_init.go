@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build hashselftest
+
+package hash
+
+// initSelfTest is the report of the power-on self-test.
+var initSelfTest *SelfTestReport
+
+// init runs the power-on self-test. The built-in hash functions are registered at package variable initialization,
+// which completes before any init function runs.
+func init() {
+	initSelfTest = SelfTest()
+	if err := initSelfTest.Err(); err != nil {
+		panic(err)
+	}
+}
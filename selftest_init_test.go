@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build hashselftest && !hash_nosha2
+
+package hash
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInitSelfTest(t *testing.T) {
+	if initSelfTest == nil || !slices.Contains(initSelfTest.Passed, SHA256) {
+		t.Fatal("expected SHA-256 to pass the power-on self-test")
+	}
+}
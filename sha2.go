@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_nosha2
+
+package hash
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"crypto/sha512"
+)
+
+// The SHA-2 functions are registered at package variable initialization, see registerBuiltin.
+var _ = registerBuiltin(registerSHA2)
+
+// registerSHA2 registers the SHA-2 functions.
+func registerSHA2() {
+	register(SHA224, sha256.New224, crypto.SHA224.String(), sha256.BlockSize, crypto.SHA224.Size(), sec112)
+	register(SHA256, sha256.New, crypto.SHA256.String(), sha256.BlockSize, crypto.SHA256.Size(), sec128)
+	register(SHA384, sha512.New384, crypto.SHA384.String(), sha512.BlockSize, crypto.SHA384.Size(), sec192)
+	register(SHA512, sha512.New, crypto.SHA512.String(), sha512.BlockSize, crypto.SHA512.Size(), sec256)
+	register(SHA512_224, sha512.New512_224, crypto.SHA512_224.String(), sha512.BlockSize, sha512.Size224, sec112)
+	register(SHA512_256, sha512.New512_256, crypto.SHA512_256.String(), sha512.BlockSize, sha512.Size256, sec128)
+}
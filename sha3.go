@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_nosha3
+
+package hash
+
+import (
+	"crypto"
+
+	"golang.org/x/crypto/sha3"
+)
+
+const (
+	// string IDs for the hash functions.
+	shake128 = "SHAKE128"
+	shake256 = "SHAKE256"
+
+	// block size in bytes.
+	blockSHA3224  = 1152 / 8
+	blockSHA3256  = 1088 / 8
+	blockSHA3384  = 832 / 8
+	blockSHA3512  = 576 / 8
+	blockSHAKE128 = 1344 / 8
+	blockSHAKE256 = 1088 / 8
+)
+
+func newShake128() XOF {
+	return sha3.NewShake128()
+}
+
+func newShake256() XOF {
+	return sha3.NewShake256()
+}
+
+func cloneSHA3(state any) (any, bool) {
+	s, ok := state.(sha3.ShakeHash)
+	if !ok {
+		return nil, false
+	}
+
+	return s.Clone(), true
+}
+
+// The SHA-3 and SHAKE functions are registered at package variable initialization, see registerBuiltin.
+var _ = registerBuiltin(registerSHA3)
+
+// registerSHA3 registers the SHA-3 and SHAKE functions.
+func registerSHA3() {
+	register(SHA3_224, sha3.New224, crypto.SHA3_224.String(), blockSHA3224, crypto.SHA3_224.Size(), sec112)
+	register(SHA3_256, sha3.New256, crypto.SHA3_256.String(), blockSHA3256, crypto.SHA3_256.Size(), sec128)
+	register(SHA3_384, sha3.New384, crypto.SHA3_384.String(), blockSHA3384, crypto.SHA3_384.Size(), sec192)
+	register(SHA3_512, sha3.New512, crypto.SHA3_512.String(), blockSHA3512, crypto.SHA3_512.Size(), sec256)
+	register(SHAKE128, newShake128, shake128, blockSHAKE128, size256, sec128)
+	register(SHAKE256, newShake256, shake256, blockSHAKE256, size256, sec224)
+
+	stateCloners = append(stateCloners, cloneSHA3)
+}
@@ -0,0 +1,332 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_nosha3
+
+package hash
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash"
+	"runtime"
+	"sync"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Function names of the NIST SP 800-185 derived functions, used for domain separation in cSHAKE.
+const (
+	kmacName         = "KMAC"
+	tupleHashName    = "TupleHash"
+	parallelHashName = "ParallelHash"
+)
+
+const (
+	// output size in bytes of the intermediate block hashes in ParallelHash.
+	parallelHashChain128 = 32
+	parallelHashChain256 = 64
+)
+
+var (
+	// ErrNotSHAKE indicates that the function is only defined over SHAKE128 or SHAKE256.
+	ErrNotSHAKE = errors.New("function is only defined for SHAKE128 and SHAKE256")
+
+	// ErrBlockSize indicates that the ParallelHash block size is not strictly positive.
+	ErrBlockSize = errors.New("invalid ParallelHash block size")
+)
+
+// leftEncode implements left_encode(x) from NIST SP 800-185.
+func leftEncode(x uint64) []byte {
+	var b [9]byte
+
+	binary.BigEndian.PutUint64(b[1:], x)
+
+	i := 1
+	for i < 8 && b[i] == 0 {
+		i++
+	}
+
+	b[i-1] = byte(9 - i)
+
+	return b[i-1:]
+}
+
+// rightEncode implements right_encode(x) from NIST SP 800-185.
+func rightEncode(x uint64) []byte {
+	var b [9]byte
+
+	binary.BigEndian.PutUint64(b[:8], x)
+
+	i := 0
+	for i < 7 && b[i] == 0 {
+		i++
+	}
+
+	b[8] = byte(8 - i)
+
+	return b[i:]
+}
+
+// encodeString implements encode_string(s) from NIST SP 800-185.
+func encodeString(s []byte) []byte {
+	return append(leftEncode(uint64(len(s))*8), s...)
+}
+
+// bytepad implements bytepad(x, w) from NIST SP 800-185.
+func bytepad(x []byte, w int) []byte {
+	out := append(leftEncode(uint64(w)), x...)
+	if padding := len(out) % w; padding != 0 {
+		out = append(out, make([]byte, w-padding)...)
+	}
+
+	return out
+}
+
+func (h Hash) newCShake(functionName, customization []byte) (sha3.ShakeHash, error) {
+	switch h {
+	case SHAKE128:
+		return sha3.NewCShake128(functionName, customization), nil
+	case SHAKE256:
+		return sha3.NewCShake256(functionName, customization), nil
+	default:
+		return nil, ErrNotSHAKE
+	}
+}
+
+func (h Hash) checkSP800185(size int) error {
+	if h != SHAKE128 && h != SHAKE256 {
+		return ErrNotSHAKE
+	}
+
+	if size < h.Size() {
+		return ErrSmallOutputSize
+	}
+
+	return nil
+}
+
+// NewCSHAKE returns a cSHAKE128 or cSHAKE256 Hasher, as defined in NIST SP 800-185, for h being SHAKE128 or SHAKE256,
+// respectively. functionName is reserved for functions defined by NIST, and customization is the user-defined
+// customization string. If both are empty, cSHAKE is equivalent to SHAKE. The Hasher's identifier is h.
+func (h Hash) NewCSHAKE(functionName, customization []byte) (*ExtendableHash, error) {
+	c, err := h.newCShake(functionName, customization)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendableHash{
+		xof: c,
+		id:  h,
+	}, nil
+}
+
+// kmac implements KMAC and KMACXOF on top of cSHAKE.
+type kmac struct {
+	sha3.ShakeHash
+	keyBlock  []byte
+	outputLen uint64
+	finalized bool
+}
+
+func (h Hash) newKMAC(key, customization []byte, outputLen uint64) (*kmac, error) {
+	c, err := h.newCShake([]byte(kmacName), customization)
+	if err != nil {
+		return nil, err
+	}
+
+	k := &kmac{
+		ShakeHash: c,
+		keyBlock:  bytepad(encodeString(key), h.BlockSize()),
+		outputLen: outputLen,
+		finalized: false,
+	}
+	_, _ = k.ShakeHash.Write(k.keyBlock)
+
+	return k, nil
+}
+
+// Read finalizes the input with the output length encoding on first use, and squeezes output.
+func (k *kmac) Read(p []byte) (int, error) {
+	if !k.finalized {
+		_, _ = k.ShakeHash.Write(rightEncode(k.outputLen))
+		k.finalized = true
+	}
+
+	return k.ShakeHash.Read(p)
+}
+
+// Sum appends the KMAC of the current state to b, without modifying the state.
+func (k *kmac) Sum(b []byte) []byte {
+	c := k.ShakeHash.Clone()
+	_, _ = c.Write(rightEncode(k.outputLen))
+
+	out := make([]byte, k.outputLen/8)
+	_, _ = c.Read(out)
+
+	return append(b, out...)
+}
+
+// Clone returns a copy of the KMAC in its current state.
+func (k *kmac) Clone() sha3.ShakeHash {
+	return &kmac{
+		ShakeHash: k.ShakeHash.Clone(),
+		keyBlock:  k.keyBlock,
+		outputLen: k.outputLen,
+		finalized: k.finalized,
+	}
+}
+
+// Size returns the KMAC output size.
+func (k *kmac) Size() int {
+	return int(k.outputLen / 8)
+}
+
+// Reset resets the KMAC to its initial keyed state.
+func (k *kmac) Reset() {
+	k.ShakeHash.Reset()
+	_, _ = k.ShakeHash.Write(k.keyBlock)
+	k.finalized = false
+}
+
+// NewKMAC returns KMAC128 or KMAC256, as defined in NIST SP 800-185, for h being SHAKE128 or SHAKE256, respectively.
+// size is the output length L in bytes, and must be at least h.Size(). The returned hash.Hash computes the MAC of the
+// written data with Sum, like the built-in HMAC.
+func (h Hash) NewKMAC(key, customization []byte, size int) (hash.Hash, error) {
+	if err := h.checkSP800185(size); err != nil {
+		return nil, err
+	}
+
+	return h.newKMAC(key, customization, uint64(size)*8)
+}
+
+// NewKMACXOF returns KMACXOF128 or KMACXOF256, as defined in NIST SP 800-185, for h being SHAKE128 or SHAKE256,
+// respectively. The Hasher's identifier is h.
+func (h Hash) NewKMACXOF(key, customization []byte) (*ExtendableHash, error) {
+	k, err := h.newKMAC(key, customization, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ExtendableHash{
+		xof: k,
+		id:  h,
+	}, nil
+}
+
+func (h Hash) tupleHash(tuple [][]byte, customization []byte, size int, outputLen uint64) ([]byte, error) {
+	if err := h.checkSP800185(size); err != nil {
+		return nil, err
+	}
+
+	c, _ := h.newCShake([]byte(tupleHashName), customization)
+
+	for _, t := range tuple {
+		_, _ = c.Write(encodeString(t))
+	}
+
+	_, _ = c.Write(rightEncode(outputLen))
+
+	out := make([]byte, size)
+	_, _ = c.Read(out)
+
+	return out, nil
+}
+
+// TupleHash returns the size bytes TupleHash128 or TupleHash256, as defined in NIST SP 800-185, of the tuple, for h
+// being SHAKE128 or SHAKE256, respectively. Each element of the tuple is unambiguously encoded, such that different
+// tuples with the same concatenation produce different outputs.
+func (h Hash) TupleHash(tuple [][]byte, customization []byte, size int) ([]byte, error) {
+	return h.tupleHash(tuple, customization, size, uint64(size)*8)
+}
+
+// TupleHashXOF returns size bytes of TupleHashXOF128 or TupleHashXOF256, as defined in NIST SP 800-185, of the tuple,
+// for h being SHAKE128 or SHAKE256, respectively.
+func (h Hash) TupleHashXOF(tuple [][]byte, customization []byte, size int) ([]byte, error) {
+	return h.tupleHash(tuple, customization, size, 0)
+}
+
+func (h Hash) parallelHash(
+	input []byte,
+	blockSize int,
+	customization []byte,
+	size int,
+	outputLen uint64,
+) ([]byte, error) {
+	if err := h.checkSP800185(size); err != nil {
+		return nil, err
+	}
+
+	if blockSize <= 0 {
+		return nil, ErrBlockSize
+	}
+
+	n := (len(input) + blockSize - 1) / blockSize
+	chained := parallelBlocks(h, input, blockSize, n)
+
+	c, _ := h.newCShake([]byte(parallelHashName), customization)
+	_, _ = c.Write(leftEncode(uint64(blockSize)))
+	_, _ = c.Write(chained)
+	_, _ = c.Write(rightEncode(uint64(n)))
+	_, _ = c.Write(rightEncode(outputLen))
+
+	out := make([]byte, size)
+	_, _ = c.Read(out)
+
+	return out, nil
+}
+
+// parallelBlocks returns the concatenation of the SHAKE outputs of the n blocks of input, which are computed
+// concurrently. The outputs are 256 bits for SHAKE128, and 512 bits for SHAKE256.
+func parallelBlocks(h Hash, input []byte, blockSize, n int) []byte {
+	outSize := parallelHashChain128
+	if h == SHAKE256 {
+		outSize = parallelHashChain256
+	}
+
+	chained := make([]byte, n*outSize)
+	workers := runtime.GOMAXPROCS(0)
+	perWorker := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+
+	for start := 0; start < n; start += perWorker {
+		end := min(start+perWorker, n)
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s, _ := h.newCShake(nil, nil)
+
+			for i := start; i < end; i++ {
+				s.Reset()
+				_, _ = s.Write(input[i*blockSize : min((i+1)*blockSize, len(input))])
+				_, _ = s.Read(chained[i*outSize : (i+1)*outSize])
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return chained
+}
+
+// ParallelHash returns the size bytes ParallelHash128 or ParallelHash256, as defined in NIST SP 800-185, of input, for
+// h being SHAKE128 or SHAKE256, respectively. The input is split into blocks of blockSize bytes, which are hashed
+// concurrently.
+func (h Hash) ParallelHash(input []byte, blockSize int, customization []byte, size int) ([]byte, error) {
+	return h.parallelHash(input, blockSize, customization, size, uint64(size)*8)
+}
+
+// ParallelHashXOF returns size bytes of ParallelHashXOF128 or ParallelHashXOF256, as defined in NIST SP 800-185, of
+// input, for h being SHAKE128 or SHAKE256, respectively.
+func (h Hash) ParallelHashXOF(input []byte, blockSize int, customization []byte, size int) ([]byte, error) {
+	return h.parallelHash(input, blockSize, customization, size, 0)
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"encoding"
+	"errors"
+)
+
+const (
+	// stateVersion is the version of the serialized state envelope.
+	stateVersion = 2
+
+	// stateHeaderSize is the size of the fixed part of the serialized state envelope header, i.e. the version, the Hash
+	// identifier, and the length of the canonical name that follows them.
+	stateHeaderSize = 3
+)
+
+var (
+	// ErrNotMarshalable indicates that the state of the hash function can't be serialized or restored, e.g. because
+	// the underlying implementation does not support it, or because it is keyed.
+	ErrNotMarshalable = errors.New("hash state can't be serialized")
+
+	// ErrInvalidState indicates that the serialized state is malformed or of an unsupported version.
+	ErrInvalidState = errors.New("invalid serialized hash state")
+
+	// ErrStateMismatch indicates that the serialized state was produced by a different hash function.
+	ErrStateMismatch = errors.New("serialized state does not match the hash function")
+)
+
+// marshalState returns the serialized state of the hash function identified by id, implemented by state.
+func marshalState(id Hash, state any) ([]byte, error) {
+	m, ok := state.(encoding.BinaryMarshaler)
+	if !ok {
+		return nil, ErrNotMarshalable
+	}
+
+	s, err := m.MarshalBinary()
+	if err != nil {
+		return nil, ErrNotMarshalable
+	}
+
+	name := id.String()
+	header := append([]byte{stateVersion, byte(id), byte(len(name))}, name...)
+
+	return append(header, s...), nil
+}
+
+// parseStateHeader validates the envelope of the serialized state, and returns the Hash identifier it records and the
+// serialized state of the underlying implementation. The canonical name recorded along the identifier must match it,
+// so that a state is never restored into another function should identifiers change.
+func parseStateHeader(data []byte) (Hash, []byte, error) {
+	if len(data) < stateHeaderSize || data[0] != stateVersion || len(data) < stateHeaderSize+int(data[2]) {
+		return 0, nil, ErrInvalidState
+	}
+
+	id, end := Hash(data[1]), stateHeaderSize+int(data[2])
+	if string(data[stateHeaderSize:end]) != id.String() {
+		return 0, nil, ErrStateMismatch
+	}
+
+	return id, data[end:], nil
+}
+
+// unmarshalState restores the serialized state into the hash function identified by id, implemented by state.
+func unmarshalState(id Hash, state any, data []byte) error {
+	recorded, s, err := parseStateHeader(data)
+	if err != nil {
+		return err
+	}
+
+	if recorded != id {
+		return ErrStateMismatch
+	}
+
+	u, ok := state.(encoding.BinaryUnmarshaler)
+	if !ok {
+		return ErrNotMarshalable
+	}
+
+	if err = u.UnmarshalBinary(s); err != nil {
+		return ErrInvalidState
+	}
+
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, and returns the current state of the hash, recording the Hash
+// identifier, its canonical name, and the serialization version. It returns ErrNotMarshalable if the underlying hash
+// does not support serialization, e.g. SHA-3, or if it is keyed.
+func (h *Fixed) MarshalBinary() ([]byte, error) {
+	return marshalState(h.id, h.hash)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and restores a state serialized by MarshalBinary. It returns
+// ErrStateMismatch if the state was produced by another hash function, and ErrInvalidState if it is malformed.
+// A zero-value Fixed is initialized to the hash function recorded in the state.
+func (h *Fixed) UnmarshalBinary(data []byte) error {
+	if h.hash == nil {
+		id, _, err := parseStateHeader(data)
+		if err != nil {
+			return err
+		}
+
+		if id.Type() != FixedOutputLength {
+			return ErrStateMismatch
+		}
+
+		*h = *id.GetHashFunction()
+	}
+
+	return unmarshalState(h.id, h.hash, data)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, and returns the current state of the hash, recording the Hash
+// identifier, its canonical name, and the serialization version. The state includes the output already read. It
+// returns ErrNotMarshalable if the underlying function does not support serialization, e.g. SHAKE or BLAKE2X, or if it
+// is keyed.
+func (h *ExtendableHash) MarshalBinary() ([]byte, error) {
+	return marshalState(h.id, h.xof)
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, and restores a state serialized by MarshalBinary. It returns
+// ErrStateMismatch if the state was produced by another hash function, and ErrInvalidState if it is malformed.
+// A zero-value ExtendableHash is initialized to the hash function recorded in the state.
+func (h *ExtendableHash) UnmarshalBinary(data []byte) error {
+	if h.xof == nil {
+		id, _, err := parseStateHeader(data)
+		if err != nil {
+			return err
+		}
+
+		if id.Type() != ExtendableOutputFunction {
+			return ErrStateMismatch
+		}
+
+		*h = *id.GetXOF()
+	}
+
+	return unmarshalState(h.id, h.xof, data)
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sync"
+)
+
+// streamBufferSize is the size in bytes of the chunks read from streams, which amortizes the cost of reads and
+// cancellation checks while fitting in the L2 cache.
+const streamBufferSize = 64 * 1024
+
+var streamBuffers = sync.Pool{
+	New: func() any {
+		b := make([]byte, streamBufferSize)
+		return &b
+	},
+}
+
+// HashReader returns size bytes of the hash of everything read from r until io.EOF, and the number of bytes read. The
+// size is ignored for fixed output length functions, and must be at least Size for extendable output functions. It
+// returns ErrUnavailable if the hash function is not available, ErrSmallOutputSize if size is too small, and the
+// first error from r other than io.EOF.
+func (h Hash) HashReader(r io.Reader, size uint) ([]byte, int64, error) {
+	return h.HashReaderContext(context.Background(), r, size)
+}
+
+// HashReaderContext is like HashReader, but stops reading with the context's error once ctx is done. The context is
+// checked between chunks, such that cancellation is effective on huge inputs.
+func (h Hash) HashReaderContext(ctx context.Context, r io.Reader, size uint) ([]byte, int64, error) {
+	if h.Type() == ExtendableOutputFunction && size < uint(h.Size()) {
+		return nil, 0, ErrSmallOutputSize
+	}
+
+	hasher, err := h.AcquireE()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer h.Release(hasher)
+
+	total, err := stream(ctx, hasher, r)
+	if err != nil {
+		return nil, total, err
+	}
+
+	return hasher.Read(int(size)), total, nil
+}
+
+// stream writes everything read from r to w in chunks, checking ctx before each one, and returns the number of bytes
+// written.
+func stream(ctx context.Context, w io.Writer, r io.Reader) (int64, error) {
+	buf, _ := streamBuffers.Get().(*[]byte)
+	defer streamBuffers.Put(buf)
+
+	var total int64
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+
+		n, err := r.Read(*buf)
+		_, _ = w.Write((*buf)[:n])
+		total += int64(n)
+
+		if errors.Is(err, io.EOF) {
+			return total, nil
+		}
+
+		if err != nil {
+			return total, err
+		}
+	}
+}
+
+// HashFile returns size bytes of the hash of the named file's contents, and the number of bytes read. The size is
+// ignored for fixed output length functions, and must be at least Size for extendable output functions. It returns
+// ErrUnavailable if the hash function is not available, ErrSmallOutputSize if size is too small, and any error from
+// opening or reading the file.
+func (h Hash) HashFile(path string, size uint) ([]byte, int64, error) {
+	return h.HashFileContext(context.Background(), path, size)
+}
+
+// HashFileContext is like HashFile, but stops reading with the context's error once ctx is done.
+func (h Hash) HashFileContext(ctx context.Context, path string, size uint) ([]byte, int64, error) {
+	if !h.Available() {
+		return nil, 0, ErrUnavailable
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	defer f.Close()
+
+	return h.HashReaderContext(ctx, f, size)
+}
@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_noblake3
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bytemare/hash"
+	"github.com/bytemare/hash/hashtest"
+)
+
+type blake3Vectors struct {
+	Key           string `json:"key"`
+	ContextString string `json:"context_string"`
+	Cases         []struct {
+		Hash      string `json:"hash"`
+		KeyedHash string `json:"keyed_hash"`
+		DeriveKey string `json:"derive_key"`
+		InputLen  int    `json:"input_len"`
+	} `json:"cases"`
+}
+
+func blake3Input(length int) []byte {
+	input := make([]byte, length)
+	for i := range input {
+		input[i] = byte(i % 251)
+	}
+
+	return input
+}
+
+func loadBlake3Vectors(t *testing.T) *blake3Vectors {
+	contents, err := os.ReadFile(filepath.Join("testdata", "blake3_test_vectors.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var v blake3Vectors
+	if err = json.Unmarshal(contents, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	return &v
+}
+
+func checkBlake3(t *testing.T, hasher hash.Hasher, input []byte, expectedHex string) {
+	t.Helper()
+
+	expected, _ := hex.DecodeString(expectedHex)
+
+	// One-shot, over the full extended output.
+	if output := hasher.Hash(uint(len(expected)), input); !bytes.Equal(output, expected) {
+		t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+	}
+
+	// Streaming, with small writes and reads.
+	hasher.Reset()
+
+	for i := 0; i < len(input); i += 7 {
+		_, _ = hasher.Write(input[i:min(i+7, len(input))])
+	}
+
+	output := hasher.Read(hasher.Size())
+	output = append(output, hasher.Read(len(expected)-len(output))...)
+
+	if !bytes.Equal(output, expected) {
+		t.Fatalf("unexpected streaming output\n\twant: %x\n\tgot : %x", expected, output)
+	}
+}
+
+func TestBlake3Vectors(t *testing.T) {
+	v := loadBlake3Vectors(t)
+
+	for _, c := range v.Cases {
+		input := blake3Input(c.InputLen)
+
+		t.Run("hash", func(t *testing.T) {
+			checkBlake3(t, hash.BLAKE3.New(), input, c.Hash)
+		})
+
+		t.Run("keyed_hash", func(t *testing.T) {
+			keyed, err := hash.BLAKE3.NewKeyed([]byte(v.Key))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkBlake3(t, keyed, input, c.KeyedHash)
+		})
+
+		t.Run("derive_key", func(t *testing.T) {
+			checkBlake3(t, hash.NewBLAKE3DeriveKey(v.ContextString), input, c.DeriveKey)
+		})
+	}
+}
+
+func TestBlake3Parallel(t *testing.T) {
+	input := blake3Input(3<<20 + 123)
+	expected := hash.BLAKE3.Hash(input)
+
+	hasher := hash.BLAKE3.New()
+	for i := 0; i < len(input); i += 1000 {
+		_, _ = hasher.Write(input[i:min(i+1000, len(input))])
+	}
+
+	if !bytes.Equal(hasher.Read(hash.BLAKE3.Size()), expected) {
+		t.Fatal("parallel and sequential outputs differ")
+	}
+}
+
+func TestBlake3KeySize(t *testing.T) {
+	for _, key := range [][]byte{nil, make([]byte, 31), make([]byte, 33)} {
+		if _, err := hash.BLAKE3.NewKeyed(key); !errors.Is(err, hash.ErrKeySize) {
+			t.Errorf("expected error %q, got %v", hash.ErrKeySize, err)
+		}
+	}
+}
+
+func TestMarshalBinaryXOFReading(t *testing.T) {
+	xof := hash.BLAKE3.GetXOF()
+	_, _ = xof.Write(testData.message)
+	_ = xof.Read(100)
+
+	state, err := xof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := hash.BLAKE3.GetXOF()
+	if err = restored.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := hash.BLAKE3.GetXOF().Hash(300, testData.message)[100:]
+	if !bytes.Equal(restored.Read(200), expected) || !bytes.Equal(xof.Read(200), expected) {
+		t.Fatal("unexpected output after restoring a reading state")
+	}
+}
+
+func TestMarshalBinaryDeriveKey(t *testing.T) {
+	context := "github.com/bytemare/hash 2024-05-01 state test"
+	xof := hash.NewBLAKE3DeriveKey(context)
+	_, _ = xof.Write(testData.message)
+
+	state, err := xof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := hash.BLAKE3.GetXOF()
+	if err = restored.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(restored.Read(32), hash.NewBLAKE3DeriveKey(context).Hash(32, testData.message)) {
+		t.Fatal("unexpected derived key after restoring state")
+	}
+}
+
+func TestCloneKeyedBlake3(t *testing.T) {
+	h, err := hash.BLAKE3.NewKeyed(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = h.Write(testData.message)
+	clone := h.Clone()
+
+	if !bytes.Equal(clone.Read(32), h.Read(32)) {
+		t.Fatal("keyed BLAKE3 clone diverged")
+	}
+}
+
+func TestConformanceBlake3DeriveKey(t *testing.T) {
+	hashtest.TestHasher(t, func() hash.Hasher {
+		return hash.NewBLAKE3DeriveKey("github.com/bytemare/hash conformance")
+	})
+}
+
+// blake3State returns the serialized state of a BLAKE3 XOF after writing length bytes and reading read bytes, and the
+// offset of the chunk state in it, i.e. right after the chaining value stack.
+func blake3State(t testing.TB, length, read int) (state []byte, chunk int) {
+	xof := hash.BLAKE3.GetXOF()
+	_, _ = xof.Write(blake3Input(length))
+
+	if read > 0 {
+		_ = xof.Read(read)
+	}
+
+	state, err := xof.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Skip the envelope, magic, key, and flags to reach the stack length.
+	stackLen := bytes.Index(state, []byte("b3\x01")) + 3 + 32 + 4
+
+	return state, stackLen + 1 + int(state[stackLen])*32
+}
+
+// inconsistentBlake3State returns a full first chunk, with chunk counter 1, but an empty chaining value stack.
+func inconsistentBlake3State(t testing.TB) []byte {
+	state, chunk := blake3State(t, 2*1024, 0)
+	stackLen := chunk - 1 - 32
+	state[stackLen] = 0
+
+	return append(state[:stackLen+1:stackLen+1], state[chunk:]...)
+}
+
+func TestUnmarshalBinaryInconsistentBlake3(t *testing.T) {
+	mutate := func(length, read int, f func(state []byte, chunk int)) []byte {
+		state, chunk := blake3State(t, length, read)
+		f(state, chunk)
+
+		return state
+	}
+
+	for _, test := range []struct {
+		name  string
+		state []byte
+	}{
+		{"stack length", inconsistentBlake3State(t)},
+		{"blocks compressed", mutate(100, 0, func(s []byte, c int) { s[c+32+8+64+1] = 16 })},
+		{"empty last block", mutate(128, 0, func(s []byte, c int) { s[c+32+8+64] = 0 })},
+		{"block padding", mutate(10, 0, func(s []byte, c int) { s[c+32+8+63] = 1 })},
+		{"chaining value", mutate(10, 0, func(s []byte, c int) { s[c]++ })},
+		{"key", mutate(10, 0, func(s []byte, c int) { s[c-1-4-32]++ })},
+		{"output count", mutate(10, 0, func(s []byte, c int) { s[c+110+1] = 1 })},
+		{"output offset", mutate(10, 100, func(s []byte, c int) { s[c+110+9] = 0 })},
+		{"output block", mutate(10, 100, func(s []byte, c int) { s[c+110+10]++ })},
+	} {
+		xof := hash.BLAKE3.GetXOF()
+		if err := xof.UnmarshalBinary(test.state); !errors.Is(err, hash.ErrInvalidState) {
+			t.Errorf("%s: expected error %q, got %v", test.name, hash.ErrInvalidState, err)
+		}
+
+		// The hasher must be left untouched.
+		_, _ = xof.Write(testData.message)
+		if !bytes.Equal(xof.Read(32), hash.BLAKE3.Hash(testData.message)) {
+			t.Errorf("%s: hasher modified by a rejected state", test.name)
+		}
+	}
+}
+
+// FuzzUnmarshalBinaryBlake3 checks that any BLAKE3 state accepted by UnmarshalBinary can be serialized back to itself,
+// and written to and read from without panicking.
+func FuzzUnmarshalBinaryBlake3(f *testing.F) {
+	for _, length := range []int{0, 1, 64, 65, 1024, 1025, 2048, 3<<10 + 5, 33 << 10} {
+		state, _ := blake3State(f, length, 0)
+		f.Add(state, []byte{1, 2, 3})
+
+		state, _ = blake3State(f, length, 100)
+		f.Add(state, []byte(nil))
+	}
+
+	f.Add(inconsistentBlake3State(f), []byte{1, 2, 3})
+
+	f.Fuzz(func(t *testing.T, state, data []byte) {
+		xof := hash.BLAKE3.GetXOF()
+		if xof.UnmarshalBinary(state) != nil {
+			return
+		}
+
+		remarshaled, err := xof.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(remarshaled, state) {
+			t.Fatalf("restored state serializes to %x, expected %x", remarshaled, state)
+		}
+
+		// The reading flag is the first byte of the output state, which ends the serialization.
+		if state[len(state)-1-8-1-64] == 0 {
+			_, _ = xof.Write(data)
+		}
+
+		_ = xof.Read(100)
+	})
+}
@@ -0,0 +1,321 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	mctCheckpoints = 100
+	mctIterations  = 1000
+)
+
+// cavpHashes maps the file name prefixes of CAVP response files to the hash functions.
+var cavpHashes = map[string]hash.Hash{
+	"SHA224":     hash.SHA224,
+	"SHA256":     hash.SHA256,
+	"SHA384":     hash.SHA384,
+	"SHA512":     hash.SHA512,
+	"SHA512_224": hash.SHA512_224,
+	"SHA512_256": hash.SHA512_256,
+	"SHA3_224":   hash.SHA3_224,
+	"SHA3_256":   hash.SHA3_256,
+	"SHA3_384":   hash.SHA3_384,
+	"SHA3_512":   hash.SHA3_512,
+	"SHAKE128":   hash.SHAKE128,
+	"SHAKE256":   hash.SHAKE256,
+}
+
+// cavpHMACHashes maps the output sizes in the [L=n] sections of the CAVP HMAC response file to the hash functions.
+var cavpHMACHashes = map[string]hash.Hash{
+	"28": hash.SHA224,
+	"32": hash.SHA256,
+	"48": hash.SHA384,
+	"64": hash.SHA512,
+}
+
+// rspSection is a set of bracketed parameters of a CAVP response file, and the entries that follow them.
+type rspSection struct {
+	params  map[string]string
+	entries []map[string]string
+}
+
+// parseRSP parses a CAVP response file, in which "[name = value]" lines set parameters, blank lines separate
+// entries of "name = value" lines, and lines starting with '#' are comments.
+func parseRSP(t *testing.T, path string) []*rspSection {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	var (
+		sections []*rspSection
+		section  *rspSection
+		entry    map[string]string
+	)
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case line == "":
+			entry = nil
+		case strings.HasPrefix(line, "["):
+			if section == nil || len(section.entries) != 0 {
+				section = &rspSection{params: map[string]string{}}
+				sections = append(sections, section)
+			}
+
+			if name, value, ok := strings.Cut(strings.Trim(line, "[]"), "="); ok {
+				section.params[strings.TrimSpace(name)] = strings.TrimSpace(value)
+			}
+		default:
+			name, value, ok := strings.Cut(line, "=")
+			if !ok {
+				t.Fatalf("malformed line %q", line)
+			}
+
+			if section == nil {
+				section = &rspSection{params: map[string]string{}}
+				sections = append(sections, section)
+			}
+
+			if entry == nil {
+				entry = map[string]string{}
+				section.entries = append(section.entries, entry)
+			}
+
+			entry[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return sections
+}
+
+func atoi(t *testing.T, s string) int {
+	t.Helper()
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return i
+}
+
+// nistDigest returns length bytes of the hash of msg. Extendable output functions are read for at least their
+// standard output size and truncated, which matches shorter outputs.
+func nistDigest(h hash.Hash, msg []byte, length int) []byte {
+	return h.New().Hash(uint(max(length, h.Size())), msg)[:length]
+}
+
+// sha2MonteCarlo returns the checkpoints of the SHA-2 Monte Carlo test, as specified in the SHAVS.
+func sha2MonteCarlo(h hash.Hash, seed []byte) [][]byte {
+	hasher := h.New()
+	checkpoints := make([][]byte, mctCheckpoints)
+
+	for j := range checkpoints {
+		md := [3][]byte{seed, seed, seed}
+
+		for range mctIterations {
+			md = [3][]byte{md[1], md[2], hasher.Hash(0, md[0], md[1], md[2])}
+		}
+
+		seed = md[2]
+		checkpoints[j] = seed
+	}
+
+	return checkpoints
+}
+
+// sha3MonteCarlo returns the checkpoints of the SHA-3 Monte Carlo test, as specified in the SHA3VS.
+func sha3MonteCarlo(h hash.Hash, seed []byte) [][]byte {
+	hasher := h.New()
+	checkpoints := make([][]byte, mctCheckpoints)
+	md := seed
+
+	for j := range checkpoints {
+		for range mctIterations {
+			md = hasher.Hash(0, md)
+		}
+
+		checkpoints[j] = md
+	}
+
+	return checkpoints
+}
+
+func monteCarlo(h hash.Hash, seed []byte) [][]byte {
+	if strings.HasPrefix(h.String(), "SHA3") {
+		return sha3MonteCarlo(h, seed)
+	}
+
+	return sha2MonteCarlo(h, seed)
+}
+
+func TestCAVP(t *testing.T) {
+	for _, file := range vectorFiles(t, "cavp", "*.rsp") {
+		t.Run(vectorName(file), func(t *testing.T) {
+			name := strings.TrimSuffix(filepath.Base(file), ".rsp")
+			sections := parseRSP(t, file)
+
+			if name == "HMAC" {
+				testCAVPHMAC(t, sections)
+				return
+			}
+
+			for _, kind := range []string{"ShortMsg", "LongMsg", "Monte", "VariableOut"} {
+				prefix, ok := strings.CutSuffix(name, kind)
+				if !ok {
+					continue
+				}
+
+				h, ok := cavpHashes[prefix]
+				if !ok {
+					t.Skipf("unsupported hash function %q", prefix)
+				}
+
+				skipUnavailable(t, h)
+
+				if kind == "Monte" {
+					if h.Type() != hash.FixedOutputLength {
+						t.Skipf("unsupported Monte Carlo test for %s", h)
+					}
+
+					testCAVPMonte(t, h, sections)
+				} else {
+					testCAVPMessages(t, h, sections)
+				}
+
+				return
+			}
+
+			t.Skipf("unsupported response file %q", name)
+		})
+	}
+}
+
+// testCAVPMessages runs the short message, long message, and variable output tests.
+func testCAVPMessages(t *testing.T, h hash.Hash, sections []*rspSection) {
+	for _, section := range sections {
+		for _, entry := range section.entries {
+			// The input length of variable output tests is a section parameter.
+			l, ok := entry["Len"]
+			if !ok {
+				l = section.params["Input Length"]
+			}
+
+			length := atoi(t, l)
+			if length%8 != 0 {
+				continue // Bit-oriented messages are not supported.
+			}
+
+			expected, ok := entry["MD"]
+			if !ok {
+				expected = entry["Output"]
+			}
+
+			md := decodeHex(t, expected)
+			msg := decodeHex(t, entry["Msg"])[:length/8]
+
+			if out, ok := entry["Outputlen"]; ok && atoi(t, out) != 8*len(md) {
+				t.Fatalf("Len = %d: inconsistent output length %s", length, out)
+			}
+
+			if digest := nistDigest(h, msg, len(md)); !bytes.Equal(digest, md) {
+				t.Fatalf("Len = %d: expected %x, got %x", length, md, digest)
+			}
+		}
+	}
+}
+
+func testCAVPMonte(t *testing.T, h hash.Hash, sections []*rspSection) {
+	var (
+		seed        []byte
+		checkpoints [][]byte
+	)
+
+	for _, section := range sections {
+		for _, entry := range section.entries {
+			if s, ok := entry["Seed"]; ok {
+				seed = decodeHex(t, s)
+			}
+
+			if md, ok := entry["MD"]; ok {
+				checkpoints = append(checkpoints, decodeHex(t, md))
+			}
+		}
+	}
+
+	if seed == nil || len(checkpoints) != mctCheckpoints {
+		t.Fatalf("expected a seed and %d checkpoints, got %d", mctCheckpoints, len(checkpoints))
+	}
+
+	for i, md := range monteCarlo(h, seed) {
+		if !bytes.Equal(md, checkpoints[i]) {
+			t.Fatalf("COUNT = %d: expected %x, got %x", i, checkpoints[i], md)
+		}
+	}
+}
+
+// testCAVPHMAC runs the HMAC tests. Keys longer than the output size are rejected by Hmac, which is checked along the
+// tag computed by crypto/hmac.
+func testCAVPHMAC(t *testing.T, sections []*rspSection) {
+	for _, section := range sections {
+		h, ok := cavpHMACHashes[section.params["L"]]
+		if !ok || !h.Available() {
+			continue // HMAC-SHA-1 is not supported.
+		}
+
+		for _, entry := range section.entries {
+			key := decodeHex(t, entry["Key"])
+			msg := decodeHex(t, entry["Msg"])
+			mac := decodeHex(t, entry["Mac"])
+
+			if len(key) != atoi(t, entry["Klen"]) || len(mac) != atoi(t, entry["Tlen"]) {
+				t.Fatalf("Count = %s: inconsistent lengths", entry["Count"])
+			}
+
+			tag, err := h.GetHashFunction().HmacE(msg, key)
+			if len(key) > h.Size() {
+				if !errors.Is(err, hash.ErrHmacKeySize) {
+					t.Fatalf("Count = %s: expected error %q, got %v", entry["Count"], hash.ErrHmacKeySize, err)
+				}
+
+				tag, err = referenceHMAC(h, msg, key), nil
+			}
+
+			if err != nil || !bytes.Equal(tag[:len(mac)], mac) {
+				t.Fatalf("Count = %s: expected %x, got %x: %v", entry["Count"], mac, tag, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func TestClone(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		hasher := h.HashID.New()
+		_, _ = hasher.Write(testData.message)
+
+		clone := hasher.Clone()
+		if clone.Algorithm() != h.HashID {
+			t.Fatalf("unexpected clone algorithm %s", clone.Algorithm())
+		}
+
+		// The clone must not be affected by writes to the original, and vice versa.
+		_, _ = hasher.Write(testData.secret)
+		_, _ = clone.Write(testData.info)
+
+		if !bytes.Equal(hasher.Read(h.outputsize), h.HashID.Hash(testData.message, testData.secret)) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+
+		if !bytes.Equal(clone.Read(h.outputsize), h.HashID.Hash(testData.message, testData.info)) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+	})
+}
+
+func TestCloneXOFReading(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType != hash.ExtendableOutputFunction {
+			return
+		}
+
+		expected := h.HashID.GetXOF().Hash(uint(3*h.outputsize), testData.message)
+
+		xof := h.HashID.GetXOF()
+		_, _ = xof.Write(testData.message)
+		_ = xof.Read(h.outputsize)
+
+		clone := xof.Clone()
+
+		if !bytes.Equal(clone.Read(h.outputsize), expected[h.outputsize:2*h.outputsize]) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+
+		if !bytes.Equal(xof.Read(2*h.outputsize), expected[h.outputsize:]) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+
+		if !bytes.Equal(clone.Read(h.outputsize), expected[2*h.outputsize:]) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+	})
+}
+
+func TestCloneKeyed(t *testing.T) {
+	skipUnavailable(t, hash.BLAKE2B_256, hash.BLAKE2S_256)
+
+	key := bytes.Repeat([]byte{1}, 32)
+
+	for _, id := range []hash.Hash{hash.BLAKE2B_256, hash.BLAKE2S_256} {
+		h, err := id.NewKeyed(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = h.GetHashFunction().CloneE(); !errors.Is(err, hash.ErrNotClonable) {
+			t.Fatalf("%s: expected error %q, got %v", id, hash.ErrNotClonable, err)
+		}
+
+		if hasPanic, err := expectPanic(hash.ErrNotClonable, func() {
+			_ = h.Clone()
+		}); !hasPanic {
+			t.Fatalf("%s: expected panic: %v", id, err)
+		}
+	}
+}
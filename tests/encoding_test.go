@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func TestParse(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		id, err := hash.Parse(h.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id != h.HashID {
+			t.Fatalf("expected %s, got %s", h.HashID, id)
+		}
+	})
+
+	for name, expected := range map[string]hash.Hash{
+		"sha256":      hash.SHA256,
+		"SHA-256":     hash.SHA256,
+		"sha2-256":    hash.SHA256,
+		"sha512_256":  hash.SHA512_256,
+		"SHA-512/224": hash.SHA512_224,
+		"sha3-256":    hash.SHA3_256,
+		"SHA3_512":    hash.SHA3_512,
+		"shake128":    hash.SHAKE128,
+		"Shake-256":   hash.SHAKE256,
+		"blake2b-256": hash.BLAKE2B_256,
+		"blake2b":     hash.BLAKE2B_512,
+		"blake2s":     hash.BLAKE2S_256,
+		"blake3":      hash.BLAKE3,
+		" BLAKE2XB ":  hash.BLAKE2XB,
+	} {
+		id, err := hash.Parse(name)
+		if !expected.Available() {
+			// Functions excluded with a build tag are unknown.
+			if !errors.Is(err, hash.ErrUnknownName) {
+				t.Fatalf("%q: expected error %q, got %v", name, hash.ErrUnknownName, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", name, err)
+		}
+
+		if id != expected {
+			t.Fatalf("%q: expected %s, got %s", name, expected, id)
+		}
+	}
+
+	for _, name := range []string{"", "-", "sha", "sha-1", "md5", "SHA-256-extra"} {
+		if _, err := hash.Parse(name); !errors.Is(err, hash.ErrUnknownName) {
+			t.Errorf("%q: expected error %q, got %v", name, hash.ErrUnknownName, err)
+		}
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	testAll(t, func(h *testHash) {
+		text, err := h.HashID.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(text) != h.name {
+			t.Fatalf("expected %q, got %q", h.name, text)
+		}
+
+		var id hash.Hash
+		if err = id.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+
+		if id != h.HashID {
+			t.Fatalf("expected %s, got %s", h.HashID, id)
+		}
+	})
+
+	if text, err := hash.Hash(0).MarshalText(); err != nil || len(text) != 0 {
+		t.Errorf("expected an empty text for the zero Hash, got %q and %v", text, err)
+	}
+
+	if _, err := hash.Hash(50).MarshalText(); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	id := hash.SHA256
+	if err := id.UnmarshalText(nil); err != nil || id != 0 {
+		t.Errorf("expected the zero Hash for an empty text, got %s and %v", id, err)
+	}
+
+	id = hash.SHA256
+	if err := id.UnmarshalText([]byte("unknown")); !errors.Is(err, hash.ErrUnknownName) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnknownName, err)
+	}
+
+	if id != hash.SHA256 {
+		t.Errorf("identifier modified on error: %s", id)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	skipUnavailable(t, hash.SHA3_256, hash.BLAKE3)
+
+	type config struct {
+		Hash  hash.Hash            `json:"hash"`
+		Named map[hash.Hash]string `json:"named"`
+	}
+
+	c := config{Hash: hash.SHA3_256, Named: map[hash.Hash]string{hash.BLAKE3: "content"}}
+
+	encoded, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"hash":"SHA3-256","named":{"BLAKE3":"content"}}`; string(encoded) != expected {
+		t.Fatalf("expected %s, got %s", expected, encoded)
+	}
+
+	var decoded config
+	if err = json.Unmarshal([]byte(`{"hash":"sha3_256","named":{"blake3":"content"}}`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Hash != hash.SHA3_256 || decoded.Named[hash.BLAKE3] != "content" {
+		t.Fatalf("unexpected decoded value %v", decoded)
+	}
+
+	for _, invalid := range []string{`{"hash":5}`, `{"hash":"unknown"}`} {
+		if err = json.Unmarshal([]byte(invalid), &decoded); err == nil {
+			t.Errorf("%s: expected error", invalid)
+		}
+	}
+
+	if _, err = json.Marshal(config{Hash: 50}); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
+
+func TestMarshalJSONZero(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	var zero struct {
+		H hash.Hash
+	}
+
+	encoded, err := json.Marshal(zero)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"H":null}`; string(encoded) != expected {
+		t.Fatalf("expected %s, got %s", expected, encoded)
+	}
+
+	decoded := zero
+	decoded.H = hash.SHA256
+
+	if err = json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	// null leaves the value unchanged, as for the other types.
+	if decoded.H != hash.SHA256 {
+		t.Fatalf("expected %s, got %s", hash.SHA256, decoded.H)
+	}
+
+	for _, data := range []string{`{"H":null}`, `{"H":""}`, `{}`} {
+		decoded = zero
+		if err = json.Unmarshal([]byte(data), &decoded); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+
+		if decoded.H != 0 {
+			t.Errorf("%s: expected the zero Hash, got %s", data, decoded.H)
+		}
+	}
+}
+
+func TestFlag(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHAKE256)
+
+	id := hash.SHA256
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.Var(&id, "hash", "hash function")
+
+	if err := flags.Parse([]string{"-hash", "shake256"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if id != hash.SHAKE256 {
+		t.Fatalf("expected %s, got %s", hash.SHAKE256, id)
+	}
+
+	if flags.Lookup("hash").DefValue != hash.SHA256.String() {
+		t.Fatalf("unexpected default value %q", flags.Lookup("hash").DefValue)
+	}
+}
@@ -0,0 +1,19 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build hash_noblake3
+
+package tests_test
+
+import "github.com/bytemare/hash"
+
+func init() {
+	excluded = append(excluded,
+		hash.BLAKE3,
+	)
+}
@@ -0,0 +1,130 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// expandVectors holds the RFC 9380 Appendix K test vectors for a message expander.
+type expandVectors struct {
+	DST   string `json:"DST"`
+	Hash  string `json:"hash"`
+	Name  string `json:"name"`
+	Tests []struct {
+		Msg          string `json:"msg"`
+		LenInBytes   string `json:"len_in_bytes"`
+		UniformBytes string `json:"uniform_bytes"`
+	} `json:"tests"`
+}
+
+func hashFromName(t *testing.T, name string) hash.Hash {
+	t.Helper()
+
+	for _, h := range builtinHashes {
+		if h.name == name {
+			skipUnavailable(t, h.HashID)
+			return h.HashID
+		}
+	}
+
+	t.Fatalf("unknown hash function %q", name)
+
+	return 0
+}
+
+func TestExpandMessageVectors(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "rfc9380", "expand_message_*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no test vectors found: %v", err)
+	}
+
+	for _, file := range files {
+		t.Run(filepath.Base(file), func(t *testing.T) {
+			contents, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var v expandVectors
+			if err = json.Unmarshal(contents, &v); err != nil {
+				t.Fatal(err)
+			}
+
+			h := hashFromName(t, v.Hash)
+
+			for i, test := range v.Tests {
+				length, err := strconv.ParseUint(strings.TrimPrefix(test.LenInBytes, "0x"), 16, 16)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expected := decodeHex(t, test.UniformBytes)
+
+				output, err := h.ExpandMessage([]byte(test.Msg), []byte(v.DST), uint(length))
+				if err != nil {
+					t.Fatalf("%d: unexpected error: %v", i, err)
+				}
+
+				if !bytes.Equal(output, expected) {
+					t.Fatalf("%d: unexpected output\n\twant: %x\n\tgot : %x", i, expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestExpandMessageErrors(t *testing.T) {
+	dst := []byte("DST")
+
+	testAll(t, func(h *testHash) {
+		if _, err := h.HashID.ExpandMessage(testData.message, nil, 32); !errors.Is(err, hash.ErrEmptyDST) {
+			t.Errorf("expected error %q, got %v", hash.ErrEmptyDST, err)
+		}
+
+		lengths := []uint{0, 65536}
+		if h.HashType == hash.FixedOutputLength {
+			lengths = append(lengths, uint(255*h.HashID.Size()+1))
+		}
+
+		for _, length := range lengths {
+			if _, err := h.HashID.ExpandMessage(testData.message, dst, length); !errors.Is(err, hash.ErrExpandLength) {
+				t.Errorf("length %d: expected error %q, got %v", length, hash.ErrExpandLength, err)
+			}
+		}
+
+		for _, length := range []uint{1, 65535} {
+			if h.HashType == hash.FixedOutputLength && length > uint(255*h.HashID.Size()) {
+				continue
+			}
+
+			output, err := h.HashID.ExpandMessage(testData.message, dst, length)
+			if err != nil {
+				t.Fatalf("length %d: unexpected error: %v", length, err)
+			}
+
+			if uint(len(output)) != length {
+				t.Errorf("length %d: invalid output length %d", length, len(output))
+			}
+		}
+	})
+
+	if _, err := hash.Hash(0).ExpandMessage(testData.message, dst, 32); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
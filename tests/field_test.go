@@ -0,0 +1,215 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	p256Modulus     = "ffffffff00000001000000000000000000000000ffffffffffffffffffffffff"
+	ed25519Modulus  = "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffed"
+	bls12381Modulus = "1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab"
+	p448Modulus     = "fffffffffffffffffffffffffffffffffffffffffffffffffffffffeffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+)
+
+// hashToFieldVector holds the field elements u of the RFC 9380 Appendix J test vectors for a suite, with its security
+// parameter k.
+type hashToFieldVector struct {
+	modulus string
+	dst     string
+	msg     string
+	u       [][]string
+	hash    hash.Hash
+	ext     uint
+	k       uint
+}
+
+var hashToFieldVectors = []hashToFieldVector{
+	{
+		hash:    hash.SHA256,
+		modulus: p256Modulus,
+		dst:     "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_",
+		msg:     "",
+		ext:     1,
+		k:       128,
+		u: [][]string{
+			{"ad5342c66a6dd0ff080df1da0ea1c04b96e0330dd89406465eeba11582515009"},
+			{"8c0f1d43204bd6f6ea70ae8013070a1518b43873bcd850aafa0a9e220e2eea5a"},
+		},
+	},
+	{
+		hash:    hash.SHA256,
+		modulus: p256Modulus,
+		dst:     "QUUX-V01-CS02-with-P256_XMD:SHA-256_SSWU_RO_",
+		msg:     "abc",
+		ext:     1,
+		k:       128,
+		u: [][]string{
+			{"afe47f2ea2b10465cc26ac403194dfb68b7f5ee865cda61e9f3e07a537220af1"},
+			{"379a27833b0bfe6f7bdca08e1e83c760bf9a338ab335542704edcd69ce9e46e0"},
+		},
+	},
+	{
+		hash:    hash.SHA256,
+		modulus: bls12381Modulus,
+		dst:     "QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_",
+		msg:     "",
+		ext:     2,
+		k:       128,
+		u: [][]string{
+			{
+				"03dbc2cce174e91ba93cbb08f26b917f98194a2ea08d1cce75b2b9cc9f21689d80bd79b594a613d0a68eb807dfdc1cf8",
+				"05a2acec64114845711a54199ea339abd125ba38253b70a92c876df10598bd1986b739cad67961eb94f7076511b3b39a",
+			},
+			{
+				"02f99798e8a5acdeed60d7e18e9120521ba1f47ec090984662846bc825de191b5b7641148c0dbc237726a334473eee94",
+				"145a81e418d4010cc027a68f14391b30074e89e60ee7a22f87217b2f6eb0c4b94c9115b436e6fa4607e95a98de30a435",
+			},
+		},
+	},
+	{
+		hash:    hash.SHAKE256,
+		modulus: p448Modulus,
+		dst:     "QUUX-V01-CS02-with-edwards448_XOF:SHAKE256_ELL2_RO_",
+		msg:     "",
+		ext:     1,
+		k:       224,
+		u: [][]string{
+			{
+				"0847c5ebf957d3370b1f98fde499fb3e659996d9fc9b5707176ade785ba72cd84b8a5597c12b1024be5f510fa5ba99642c4cec7f3f69d3e7",
+			},
+			{
+				"f8cbd8a7ae8c8deed071f3ac4b93e7cfcb8f1eac1645d699fd6d3881cb295a5d3006d9449ed7cad412a77a1fe61e84a9e41d59ef384d6f9a",
+			},
+		},
+	},
+	{
+		hash:    hash.SHA512,
+		modulus: ed25519Modulus,
+		dst:     "QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_",
+		msg:     "",
+		ext:     1,
+		k:       128,
+		u: [][]string{
+			{"03fef4813c8cb5f98c6eef88fae174e6e7d5380de2b007799ac7ee712d203f3a"},
+			{"780bdddd137290c8f589dc687795aafae35f6b674668d92bf92ae793e6a60c75"},
+		},
+	},
+	{
+		hash:    hash.SHA512,
+		modulus: ed25519Modulus,
+		dst:     "QUUX-V01-CS02-with-edwards25519_XMD:SHA-512_ELL2_RO_",
+		msg:     "abc",
+		ext:     1,
+		k:       128,
+		u: [][]string{
+			{"5081955c4141e4e7d02ec0e36becffaa1934df4d7a270f70679c78f9bd57c227"},
+			{"005bdc17a9b378b6272573a31b04361f21c371b256252ae5463119aa0b925b76"},
+		},
+	},
+}
+
+func parseModulus(t *testing.T, s string) *big.Int {
+	t.Helper()
+
+	p, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		t.Fatalf("invalid modulus %q", s)
+	}
+
+	return p
+}
+
+func equalElements(a, b []*big.Int) bool {
+	return slices.EqualFunc(a, b, func(x, y *big.Int) bool { return x.Cmp(y) == 0 })
+}
+
+func TestHashToFieldVectors(t *testing.T) {
+	for i, v := range hashToFieldVectors {
+		if !v.hash.Available() {
+			continue
+		}
+
+		modulus := parseModulus(t, v.modulus)
+
+		u, err := v.hash.HashToFieldK([]byte(v.msg), []byte(v.dst), uint(len(v.u)), v.ext, v.k, modulus)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		// HashToField only matches the suites whose security parameter is the security level of the hash function.
+		if v.k == uint(v.hash.SecurityLevel()) {
+			withLevel, err := v.hash.HashToField([]byte(v.msg), []byte(v.dst), uint(len(v.u)), v.ext, modulus)
+			if err != nil || !slices.EqualFunc(withLevel, u, equalElements) {
+				t.Fatalf("%d: expected the same elements as with an explicit k: %v", i, err)
+			}
+		}
+
+		if len(u) != len(v.u) {
+			t.Fatalf("%d: expected %d elements, got %d", i, len(v.u), len(u))
+		}
+
+		for j, element := range v.u {
+			if len(u[j]) != len(element) {
+				t.Fatalf("%d: expected %d components, got %d", i, len(element), len(u[j]))
+			}
+
+			for k, component := range element {
+				if expected := parseModulus(t, component); u[j][k].Cmp(expected) != 0 {
+					t.Fatalf("%d: unexpected u[%d][%d]\n\twant: %x\n\tgot : %x", i, j, k, expected, u[j][k])
+				}
+			}
+		}
+	}
+}
+
+func TestHashToFieldErrors(t *testing.T) {
+	dst := []byte("DST")
+	modulus := parseModulus(t, p256Modulus)
+
+	testAll(t, func(h *testHash) {
+		for _, m := range []*big.Int{nil, big.NewInt(-7), big.NewInt(0), big.NewInt(1)} {
+			if _, err := h.HashID.HashToField(testData.message, dst, 2, 1, m); !errors.Is(err, hash.ErrInvalidModulus) {
+				t.Errorf("modulus %v: expected error %q, got %v", m, hash.ErrInvalidModulus, err)
+			}
+		}
+
+		if _, err := h.HashID.HashToField(testData.message, dst, 0, 1, modulus); !errors.Is(err, hash.ErrExpandLength) {
+			t.Errorf("expected error %q, got %v", hash.ErrExpandLength, err)
+		}
+
+		if _, err := h.HashID.HashToField(testData.message, nil, 2, 1, modulus); !errors.Is(err, hash.ErrEmptyDST) {
+			t.Errorf("expected error %q, got %v", hash.ErrEmptyDST, err)
+		}
+
+		if length := h.HashID.FieldElementLength(modulus); length != uint((256+h.HashID.SecurityLevel()+7)/8) {
+			t.Errorf("unexpected field element length %d", length)
+		}
+
+		_, err := h.HashID.HashToFieldK(testData.message, dst, 2, 1, 128, nil)
+		if !errors.Is(err, hash.ErrInvalidModulus) {
+			t.Errorf("expected error %q, got %v", hash.ErrInvalidModulus, err)
+		}
+	})
+
+	// The edwards25519_XMD:SHA-512_ELL2_RO_ suite uses k = 128, below the security level of SHA-512, and L = 48.
+	if length := hash.FieldElementLengthK(parseModulus(t, ed25519Modulus), 128); length != 48 {
+		t.Errorf("expected a field element length of 48, got %d", length)
+	}
+
+	if _, err := hash.Hash(0).HashToField(testData.message, dst, 2, 1, modulus); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
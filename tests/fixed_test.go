@@ -9,15 +9,18 @@
 package tests_test
 
 import (
+	"bytes"
 	"encoding/hex"
 	"errors"
+	stdhash "hash"
 	"testing"
 
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+
 	"github.com/bytemare/hash"
 )
 
-var errHmacKeySize = errors.New("hmac key length is larger than hash output size")
-
 func TestHmac(t *testing.T) {
 	testAll(t, func(h *testHash) {
 		if h.HashType == hash.FixedOutputLength {
@@ -40,11 +43,34 @@ func TestLongHmacKey(t *testing.T) {
 		if h.HashType == hash.FixedOutputLength {
 			hasher := h.HashID.GetHashFunction()
 
-			if panics, err := expectPanic(errHmacKeySize, func() {
+			if panics, err := expectPanic(hash.ErrHmacKeySize, func() {
 				_ = hasher.Hmac(testData.message, longHMACKey)
 			}); !panics {
 				t.Errorf("expected panic: %v", err)
 			}
+
+			if _, err := hasher.HmacE(testData.message, longHMACKey); !errors.Is(err, hash.ErrHmacKeySize) {
+				t.Errorf("expected error %q, got %v", hash.ErrHmacKeySize, err)
+			}
+		}
+	})
+}
+
+func TestHmacE(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType == hash.FixedOutputLength {
+			hasher := h.HashID.GetHashFunction()
+
+			key, _ := hex.DecodeString(testData.key[h.HashID.Size()])
+
+			hmac, err := hasher.HmacE(testData.message, key)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(hmac, hasher.Hmac(testData.message, key)) {
+				t.Errorf(fmtExpectedEquality, h.HashID)
+			}
 		}
 	})
 }
@@ -99,3 +125,147 @@ func TestHKDFExpand(t *testing.T) {
 		}
 	})
 }
+
+func TestHKDFE(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType == hash.FixedOutputLength {
+			hasher := h.HashID.GetHashFunction()
+
+			key, err := hasher.HKDFE(testData.secret, testData.salt, testData.info, 0)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !bytes.Equal(key, hasher.HKDF(testData.secret, testData.salt, testData.info, 0)) {
+				t.Errorf(fmtExpectedEquality, h.HashID)
+			}
+
+			prk := hasher.HKDFExtract(testData.secret, testData.salt)
+
+			key, err = hasher.HKDFExpandE(prk, testData.info, 255*h.HashID.Size())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(key) != 255*h.HashID.Size() {
+				t.Errorf("#%v : invalid key length", h.HashID)
+			}
+		}
+	})
+}
+
+// TestHKDFInvalidLength checks that HKDF and HKDFExpand panic with ErrHKDFLength above the maximum output length of 255
+// times the hash output size, instead of returning a key padded with zeros, and that they accept the maximum length.
+func TestHKDFInvalidLength(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType == hash.FixedOutputLength {
+			hasher := h.HashID.GetHashFunction()
+			prk := hasher.HKDFExtract(testData.secret, testData.salt)
+			maxLength := 255 * h.HashID.Size()
+
+			if len(hasher.HKDF(testData.secret, testData.salt, testData.info, maxLength)) != maxLength ||
+				len(hasher.HKDFExpand(prk, testData.info, maxLength)) != maxLength {
+				t.Errorf("#%v : invalid key length", h.HashID)
+			}
+
+			for _, l := range []int{-1, maxLength + 1} {
+				if _, err := hasher.HKDFE(testData.secret, testData.salt, testData.info, l); !errors.Is(
+					err,
+					hash.ErrHKDFLength,
+				) {
+					t.Errorf("#%v : expected error %q for length %d, got %v", h.HashID, hash.ErrHKDFLength, l, err)
+				}
+
+				if _, err := hasher.HKDFExpandE(prk, testData.info, l); !errors.Is(err, hash.ErrHKDFLength) {
+					t.Errorf("#%v : expected error %q for length %d, got %v", h.HashID, hash.ErrHKDFLength, l, err)
+				}
+
+				if panics, err := expectPanic(hash.ErrHKDFLength, func() {
+					_ = hasher.HKDF(testData.secret, testData.salt, testData.info, l)
+				}); !panics {
+					t.Errorf("expected panic: %v", err)
+				}
+
+				if panics, err := expectPanic(hash.ErrHKDFLength, func() {
+					_ = hasher.HKDFExpand(prk, testData.info, l)
+				}); !panics {
+					t.Errorf("expected panic: %v", err)
+				}
+			}
+		}
+	})
+}
+
+func TestFixedMatchesCrypto(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType == hash.FixedOutputLength {
+			reference := h.cryptoID.New()
+			_, _ = reference.Write(testData.message)
+
+			if !bytes.Equal(h.HashID.Hash(testData.message), reference.Sum(nil)) {
+				t.Errorf(fmtExpectedEquality, h.HashID)
+			}
+		}
+	})
+}
+
+func TestKeyed(t *testing.T) {
+	key := []byte("key")
+	keyedReferences := map[hash.Hash]func(key []byte) (stdhash.Hash, error){
+		hash.BLAKE2B_256: blake2b.New256,
+		hash.BLAKE2B_384: blake2b.New384,
+		hash.BLAKE2B_512: blake2b.New512,
+		hash.BLAKE2S_256: blake2s.New256,
+	}
+
+	testAll(t, func(h *testHash) {
+		if h.HashID == hash.BLAKE3 {
+			// BLAKE3's keyed mode is tested against its official test vectors.
+			return
+		}
+
+		newReference, ok := keyedReferences[h.HashID]
+		if !ok {
+			if _, err := h.HashID.NewKeyed(key); !errors.Is(err, hash.ErrNoKeyedMode) {
+				t.Errorf("expected error %q, got %v", hash.ErrNoKeyedMode, err)
+			}
+
+			return
+		}
+
+		keyed, err := h.HashID.NewKeyed(key)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		reference, _ := newReference(key)
+		_, _ = reference.Write(testData.message)
+		expected := reference.Sum(nil)
+
+		if !bytes.Equal(keyed.Hash(0, testData.message), expected) {
+			t.Errorf(fmtExpectedEquality, h.HashID)
+		}
+
+		// Reset must preserve the key.
+		keyed.Reset()
+		_, _ = keyed.Write(testData.message)
+
+		if !bytes.Equal(keyed.Sum(nil), expected) {
+			t.Errorf(fmtExpectedEquality, h.HashID)
+		}
+
+		if bytes.Equal(h.HashID.Hash(testData.message), expected) {
+			t.Errorf("%s: keyed and unkeyed outputs must differ", h.HashID)
+		}
+
+		for _, invalid := range [][]byte{nil, make([]byte, 65)} {
+			if _, err = h.HashID.NewKeyed(invalid); !errors.Is(err, hash.ErrKeySize) {
+				t.Errorf("expected error %q, got %v", hash.ErrKeySize, err)
+			}
+		}
+	})
+
+	if _, err := hash.Hash(0).NewKeyed(key); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// addFuzzSeeds adds a seed corpus entry for every hash function.
+func addFuzzSeeds(f *testing.F) {
+	for i := range testHashes {
+		f.Add(uint8(i), testData.message, []byte{1, 7, 64, 200})
+		f.Add(uint8(i), bytes.Repeat(testData.secret, 20), []byte{0, 255, 3})
+		f.Add(uint8(i), []byte{}, []byte{})
+	}
+}
+
+// fuzzHash returns the tested hash function selected by the fuzzer.
+func fuzzHash(index uint8) *testHash {
+	return testHashes[int(index)%len(testHashes)]
+}
+
+// chunks splits data into chunks of the sizes in cuts, followed by the remainder.
+func chunks(data, cuts []byte) [][]byte {
+	c := make([][]byte, 0, len(cuts)+1)
+
+	for _, cut := range cuts {
+		n := min(int(cut), len(data))
+		c = append(c, data[:n])
+		data = data[n:]
+	}
+
+	return append(c, data)
+}
+
+// FuzzStreaming checks that writing the input in arbitrary chunks is equivalent to hashing it at once, and that the
+// output of Sum, Read, and HashInto is consistent.
+func FuzzStreaming(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, index uint8, data, cuts []byte) {
+		h := fuzzHash(index)
+		expected := h.HashID.Hash(data)
+		hasher := h.HashID.New()
+
+		for _, chunk := range chunks(data, cuts) {
+			_, _ = hasher.Write(chunk)
+		}
+
+		if digest := h.HashID.HashInto(make([]byte, h.outputsize), chunks(data, cuts)...); !bytes.Equal(
+			digest,
+			expected,
+		) {
+			t.Fatalf("%s: HashInto of the chunks %x differs from Hash %x", h.name, digest, expected)
+		}
+
+		if h.HashType == hash.FixedOutputLength {
+			sum := hasher.Sum(nil)
+			if !bytes.Equal(sum, expected) || !bytes.Equal(hasher.Read(h.outputsize), sum) ||
+				!bytes.Equal(hasher.Sum(nil), sum) {
+				t.Fatalf("%s: inconsistent streaming output %x, expected %x", h.name, sum, expected)
+			}
+
+			return
+		}
+
+		// Successive reads from an XOF continue its output.
+		long := h.HashID.New().Hash(uint(2*h.outputsize), data)
+		output := append(hasher.Sum(nil), hasher.Read(h.outputsize)...)
+
+		if !bytes.Equal(output, long) {
+			t.Fatalf("%s: inconsistent streaming output %x, expected %x", h.name, output, long)
+		}
+	})
+}
+
+// FuzzMarshalBinary checks that the state serialized after arbitrary input resumes to the same output.
+func FuzzMarshalBinary(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, index uint8, data, cuts []byte) {
+		h := fuzzHash(index)
+		c := chunks(data, cuts)
+		hasher := newMarshalingHasher(h.HashID)
+		_, _ = hasher.Write(c[0])
+
+		state, err := hasher.MarshalBinary()
+		if errors.Is(err, hash.ErrNotMarshalable) {
+			t.Skipf("%s: state serialization is not supported", h.name)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resumed := newMarshalingHasher(h.HashID)
+		if err = resumed.UnmarshalBinary(state); err != nil {
+			t.Fatalf("%s: %v", h.name, err)
+		}
+
+		for _, chunk := range c[1:] {
+			_, _ = resumed.Write(chunk)
+		}
+
+		if sum, expected := resumed.Sum(nil), h.HashID.Hash(data); !bytes.Equal(sum, expected) {
+			t.Fatalf("%s: resumed state outputs %x, expected %x", h.name, sum, expected)
+		}
+	})
+}
+
+// FuzzCryptoHash compares the fixed output length functions with the implementations registered in the standard
+// library's crypto package.
+func FuzzCryptoHash(f *testing.F) {
+	addFuzzSeeds(f)
+
+	f.Fuzz(func(t *testing.T, index uint8, data, cuts []byte) {
+		h := fuzzHash(index)
+		if h.HashType != hash.FixedOutputLength || !h.cryptoID.Available() {
+			t.Skipf("%s has no crypto.Hash equivalent", h.name)
+		}
+
+		reference := h.cryptoID.New()
+		for _, chunk := range chunks(data, cuts) {
+			_, _ = reference.Write(chunk)
+		}
+
+		if digest, expected := h.HashID.Hash(data), reference.Sum(nil); !bytes.Equal(digest, expected) {
+			t.Fatalf("%s: got %x, crypto.Hash returned %x", h.name, digest, expected)
+		}
+	})
+}
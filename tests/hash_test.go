@@ -12,6 +12,7 @@ import (
 	"bytes"
 	"crypto"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/bytemare/hash"
@@ -45,11 +46,23 @@ func TestID(t *testing.T) {
 }
 
 func TestAvailability(t *testing.T) {
-	testAll(t, func(h *testHash) {
+	for _, h := range builtinHashes {
+		if slices.Contains(excluded, h.HashID) {
+			if h.HashID.Available() {
+				t.Errorf("%s is available, but should be excluded", h.name)
+			}
+
+			if _, err := h.HashID.NewE(); !errors.Is(err, hash.ErrUnavailable) {
+				t.Errorf("%s: expected error %q, got %v", h.name, hash.ErrUnavailable, err)
+			}
+
+			continue
+		}
+
 		if !h.HashID.Available() {
-			t.Errorf("%v is not available, but should be", h.HashID)
+			t.Errorf("%s is not available, but should be", h.name)
 		}
-	})
+	}
 }
 
 func TestNonAvailability(t *testing.T) {
@@ -90,7 +103,9 @@ func TestHashType(t *testing.T) {
 }
 
 func TestNoHashType(t *testing.T) {
-	values := []hash.Hash{0, 20, 25, 50}
+	fixed, xof := registerCustom(t)
+
+	values := []hash.Hash{0, 20, max(fixed, xof) + 1}
 	for _, wrongID := range values {
 		if wrongID.Type() != "" {
 			t.Error("expected empty string")
@@ -232,11 +247,51 @@ func TestReadXOFSmallSize(t *testing.T) {
 		if h.HashType == hash.ExtendableOutputFunction {
 			hasher := h.HashID.New()
 
-			if panics, err := expectPanic(errors.New("requested output size too small"), func() {
+			if panics, err := expectPanic(hash.ErrSmallOutputSize, func() {
 				_ = hasher.Read(1)
 			}); !panics {
 				t.Errorf("expected panic: %v", err)
 			}
+
+			if _, err := hasher.GetXOF().ReadE(1); !errors.Is(err, hash.ErrSmallOutputSize) {
+				t.Errorf("expected error %q, got %v", hash.ErrSmallOutputSize, err)
+			}
+
+			if _, err := hasher.GetXOF().HashE(1, testData.message); !errors.Is(err, hash.ErrSmallOutputSize) {
+				t.Errorf("expected error %q, got %v", hash.ErrSmallOutputSize, err)
+			}
 		}
 	})
 }
+
+func TestHashE(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		hashed, err := h.HashID.HashE(testData.message)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !bytes.Equal(hashed, h.HashID.Hash(testData.message)) {
+			t.Errorf(fmtExpectedEquality, h.HashID)
+		}
+	})
+}
+
+func TestUnavailable(t *testing.T) {
+	values := []hash.Hash{0, hash.Hash(crypto.MD4), 20, 50, 255}
+	for _, wrongID := range values {
+		if _, err := wrongID.HashE(testData.message); !errors.Is(err, hash.ErrUnavailable) {
+			t.Errorf("%d: expected error %q, got %v", wrongID, hash.ErrUnavailable, err)
+		}
+
+		if _, err := wrongID.NewE(); !errors.Is(err, hash.ErrUnavailable) {
+			t.Errorf("%d: expected error %q, got %v", wrongID, hash.ErrUnavailable, err)
+		}
+
+		if panics, err := expectPanic(hash.ErrUnavailable, func() {
+			_ = wrongID.Hash(testData.message)
+		}); !panics {
+			t.Errorf("expected panic: %v", err)
+		}
+	}
+}
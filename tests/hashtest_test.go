@@ -0,0 +1,61 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/bytemare/hash"
+	"github.com/bytemare/hash/hashtest"
+)
+
+func TestConformance(t *testing.T) {
+	// testAll would hand the parent test to the callback, so the subtests are nested here.
+	for _, h := range testHashes {
+		t.Run(h.name, func(t *testing.T) {
+			t.Run("Hasher", func(t *testing.T) {
+				hashtest.TestHasher(t, h.HashID.New)
+			})
+
+			t.Run("SafeHasher", func(t *testing.T) {
+				hashtest.TestHasher(t, func() hash.Hasher {
+					return h.HashID.NewSafe()
+				})
+			})
+		})
+	}
+}
+
+func TestConformanceKeyed(t *testing.T) {
+	key, _ := hex.DecodeString(testData.key[32])
+
+	keyed := []hash.Hash{hash.BLAKE2B_256, hash.BLAKE2B_384, hash.BLAKE2B_512, hash.BLAKE2S_256, hash.BLAKE3}
+
+	for _, id := range keyed {
+		t.Run(id.String(), func(t *testing.T) {
+			skipUnavailable(t, id)
+
+			hashtest.TestHasher(t, func() hash.Hasher {
+				h, err := id.NewKeyed(key)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				return h
+			})
+		})
+	}
+}
+
+func TestConformanceRegistered(t *testing.T) {
+	id, _ := registerCustom(t)
+
+	hashtest.TestHasher(t, id.New)
+}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// sumIntoHasher is implemented by the Hashers of the package, beyond the Hasher interface.
+type sumIntoHasher interface {
+	hash.Hasher
+	SumInto(dst []byte) []byte
+}
+
+// plainHasher hides all but the methods of the Hasher interface of the embedded Hasher.
+type plainHasher struct {
+	hash.Hasher
+}
+
+func TestHashInto(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		expected := h.HashID.Hash(testData.message, testData.secret)
+		hasher := h.HashID.New()
+
+		// A larger buffer is filled by XOFs, while fixed output length functions only write the prefix.
+		size := 2 * h.outputsize
+		long := hasher.Hash(uint(size), testData.message, testData.secret)
+		if h.HashType == hash.FixedOutputLength {
+			size = h.outputsize
+			long = expected
+		}
+
+		hashers := map[string]func([]byte, ...[]byte) []byte{
+			"Hash":       h.HashID.HashInto,
+			"SafeHasher": h.HashID.NewSafe().HashInto,
+			"plain":      hash.NewSafeHasher(plainHasher{h.HashID.New()}).HashInto,
+		}
+		if h.HashType == hash.FixedOutputLength {
+			hashers["Fixed"] = hasher.GetHashFunction().HashInto
+		} else {
+			hashers["ExtendableHash"] = hasher.GetXOF().HashInto
+		}
+
+		for name, hashInto := range hashers {
+			dst := make([]byte, h.outputsize)
+			if output := hashInto(dst, testData.message, testData.secret); !bytes.Equal(output, expected) ||
+				&output[0] != &dst[0] {
+				t.Fatalf("%s: expected %x in dst, got %x", name, expected, output)
+			}
+
+			dst = bytes.Repeat([]byte{0xff}, 2*h.outputsize)
+			if output := hashInto(dst, testData.message, testData.secret); !bytes.Equal(output, long) {
+				t.Fatalf("%s: expected %x, got %x", name, long, output)
+			}
+
+			if !bytes.Equal(dst[size:], bytes.Repeat([]byte{0xff}, len(dst)-size)) {
+				t.Fatalf("%s: unexpected write beyond the output", name)
+			}
+
+			if hasPanic, err := expectPanic(hash.ErrSmallOutputSize, func() {
+				_ = hashInto(make([]byte, h.outputsize-1), testData.message)
+			}); !hasPanic {
+				t.Fatalf("%s: expected panic: %v", name, err)
+			}
+		}
+	})
+}
+
+func TestHashIntoUnavailable(t *testing.T) {
+	if hasPanic, err := expectPanic(hash.ErrUnavailable, func() {
+		_ = hash.Hash(0).HashInto(make([]byte, 64), testData.message)
+	}); !hasPanic {
+		t.Fatalf("expected panic: %v", err)
+	}
+}
+
+func TestSumInto(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		hasher, ok := h.HashID.New().(sumIntoHasher)
+		if !ok {
+			t.Fatalf("%s: SumInto is not implemented", h.name)
+		}
+
+		_, _ = hasher.Write(testData.message)
+
+		reference := hasher.Clone()
+		dst := make([]byte, h.outputsize)
+
+		if output := hasher.SumInto(dst); !bytes.Equal(output, reference.Sum(nil)) {
+			t.Fatalf("expected %x, got %x", reference.Sum(nil), output)
+		}
+
+		// Fixed output length functions keep their state, and XOFs continue their output.
+		if output := hasher.SumInto(dst); !bytes.Equal(output, reference.Sum(nil)) {
+			t.Fatalf("expected %x, got %x", reference.Sum(nil), output)
+		}
+
+		if hasPanic, err := expectPanic(hash.ErrSmallOutputSize, func() {
+			_ = hasher.SumInto(make([]byte, h.outputsize-1))
+		}); !hasPanic {
+			t.Fatalf("expected panic: %v", err)
+		}
+	})
+}
+
+func TestHashIntoAllocations(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		hasher := h.HashID.New()
+		dst := make([]byte, 2*h.outputsize)
+
+		for name, f := range map[string]func(){
+			"Hash": func() { _ = h.HashID.HashInto(dst, testData.message, testData.secret) },
+			"Fixed": func() {
+				if fixed := hasher.GetHashFunction(); fixed != nil {
+					_ = fixed.HashInto(dst, testData.message, testData.secret)
+				}
+			},
+			"ExtendableHash": func() {
+				if xof := hasher.GetXOF(); xof != nil {
+					_ = xof.HashInto(dst, testData.message, testData.secret)
+				}
+			},
+		} {
+			if allocs := testing.AllocsPerRun(100, f); allocs != 0 {
+				t.Errorf("%s: expected no allocations, got %v", name, allocs)
+			}
+		}
+	})
+}
+
+func benchmarkAll(b *testing.B, f func(b *testing.B, h *testHash)) {
+	for _, h := range testHashes {
+		b.Run(h.name, func(b *testing.B) {
+			b.ReportAllocs()
+			f(b, h)
+		})
+	}
+}
+
+func BenchmarkHash(b *testing.B) {
+	benchmarkAll(b, func(b *testing.B, h *testHash) {
+		for range b.N {
+			_ = h.HashID.Hash(testData.message)
+		}
+	})
+}
+
+func BenchmarkHashInto(b *testing.B) {
+	benchmarkAll(b, func(b *testing.B, h *testHash) {
+		dst := make([]byte, h.outputsize)
+
+		for range b.N {
+			_ = h.HashID.HashInto(dst, testData.message)
+		}
+	})
+}
+
+func BenchmarkHasherHashInto(b *testing.B) {
+	benchmarkAll(b, func(b *testing.B, h *testHash) {
+		hasher := h.HashID.New()
+		dst := make([]byte, h.outputsize)
+
+		if fixed := hasher.GetHashFunction(); fixed != nil {
+			for range b.N {
+				_ = fixed.HashInto(dst, testData.message)
+			}
+		} else {
+			xof := hasher.GetXOF()
+			for range b.N {
+				_ = xof.HashInto(dst, testData.message)
+			}
+		}
+	})
+}
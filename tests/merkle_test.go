@@ -0,0 +1,374 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+	"github.com/bytemare/hash/merkle"
+)
+
+// merkleLeaves are the leaves of the Certificate Transparency reference tests.
+var merkleLeaves = [][]byte{
+	{},
+	{0x00},
+	{0x10},
+	{0x20, 0x21},
+	{0x30, 0x31},
+	{0x40, 0x41, 0x42, 0x43},
+	{0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57},
+	{0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f},
+}
+
+// merkleRoots are the SHA-256 roots of the trees of the first 1 to 8 merkleLeaves.
+var merkleRoots = []string{
+	"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+	"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+	"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+	"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+	"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+	"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+}
+
+var merkleInclusionVectors = []struct {
+	proof       []string
+	index, size uint64
+}{
+	{nil, 0, 1},
+	{
+		[]string{
+			"96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+			"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+			"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4",
+		},
+		0, 8,
+	},
+	{
+		[]string{
+			"bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b",
+			"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+			"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		},
+		5, 8,
+	},
+	{[]string{"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125"}, 2, 3},
+}
+
+var merkleConsistencyVectors = []struct {
+	proof            []string
+	oldSize, newSize uint64
+}{
+	{nil, 1, 1},
+	{
+		[]string{
+			"96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+			"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+			"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4",
+		},
+		1, 8,
+	},
+	{
+		[]string{
+			"0298d122906dcfc10892cb53a73992fc5b9f493ea4c9badb27b791b4127a7fe7",
+			"07506a85fd9dd2f120eb694f86011e5bb4662e5c415a62917033d4a9624487e7",
+			"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+			"837dbb152e9b079010717e84e865da4ebc0fa198a806d59d31bf15accef22d0e",
+		},
+		3, 7,
+	},
+	{[]string{"837dbb152e9b079010717e84e865da4ebc0fa198a806d59d31bf15accef22d0e"}, 4, 7},
+	{
+		[]string{
+			"0ebc5d3437fbe2db158b9f126a1d118e308181031d0a949f8dededebc558ef6a",
+			"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+			"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+		},
+		6, 8,
+	},
+}
+
+func decodeProof(t *testing.T, proof []string) [][]byte {
+	t.Helper()
+
+	p := make([][]byte, len(proof))
+	for i, s := range proof {
+		p[i] = decodeHex(t, s)
+	}
+
+	return p
+}
+
+func newMerkleTree(t *testing.T, h hash.Hash, leaves [][]byte) *merkle.Tree {
+	t.Helper()
+
+	tree, err := merkle.New(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, leaf := range leaves {
+		if index := tree.Append(leaf); index != uint64(i) {
+			t.Fatalf("unexpected leaf index %d, want %d", index, i)
+		}
+	}
+
+	return tree
+}
+
+func TestMerkleRoots(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	tree := newMerkleTree(t, hash.SHA256, nil)
+
+	if !bytes.Equal(tree.Root(), hash.SHA256.Hash(nil)) {
+		t.Fatal("unexpected empty tree root")
+	}
+
+	for i, leaf := range merkleLeaves {
+		tree.Append(leaf)
+
+		if !bytes.Equal(tree.Root(), decodeHex(t, merkleRoots[i])) {
+			t.Fatalf("size %d: unexpected root %x", i+1, tree.Root())
+		}
+	}
+
+	for i, expected := range merkleRoots {
+		root, err := tree.RootAt(uint64(i + 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(root, decodeHex(t, expected)) {
+			t.Fatalf("size %d: unexpected root %x", i+1, root)
+		}
+	}
+
+	if _, err := tree.RootAt(tree.Size() + 1); !errors.Is(err, merkle.ErrInvalidSize) {
+		t.Errorf("expected error %q, got %v", merkle.ErrInvalidSize, err)
+	}
+}
+
+func TestMerkleInclusionVectors(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	tree := newMerkleTree(t, hash.SHA256, merkleLeaves)
+
+	for _, v := range merkleInclusionVectors {
+		proof, err := tree.InclusionProof(v.index, v.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := decodeProof(t, v.proof)
+		if len(proof) != len(expected) {
+			t.Fatalf("%d/%d: unexpected proof length %d", v.index, v.size, len(proof))
+		}
+
+		for i := range proof {
+			if !bytes.Equal(proof[i], expected[i]) {
+				t.Fatalf("%d/%d: unexpected proof element %d: %x", v.index, v.size, i, proof[i])
+			}
+		}
+
+		leaf, _ := merkle.LeafHash(hash.SHA256, merkleLeaves[v.index])
+		root := decodeHex(t, merkleRoots[v.size-1])
+
+		if err = merkle.VerifyInclusion(hash.SHA256, v.index, v.size, leaf, proof, root); err != nil {
+			t.Fatalf("%d/%d: unexpected error: %v", v.index, v.size, err)
+		}
+	}
+}
+
+func TestMerkleConsistencyVectors(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	tree := newMerkleTree(t, hash.SHA256, merkleLeaves)
+
+	for _, v := range merkleConsistencyVectors {
+		proof, err := tree.ConsistencyProof(v.oldSize, v.newSize)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := decodeProof(t, v.proof)
+		if len(proof) != len(expected) {
+			t.Fatalf("%d/%d: unexpected proof length %d", v.oldSize, v.newSize, len(proof))
+		}
+
+		for i := range proof {
+			if !bytes.Equal(proof[i], expected[i]) {
+				t.Fatalf("%d/%d: unexpected proof element %d: %x", v.oldSize, v.newSize, i, proof[i])
+			}
+		}
+
+		oldRoot := decodeHex(t, merkleRoots[v.oldSize-1])
+		newRoot := decodeHex(t, merkleRoots[v.newSize-1])
+
+		if err = merkle.VerifyConsistency(hash.SHA256, v.oldSize, v.newSize, oldRoot, newRoot, proof); err != nil {
+			t.Fatalf("%d/%d: unexpected error: %v", v.oldSize, v.newSize, err)
+		}
+	}
+}
+
+// tamper returns copies of the proof with each element flipped, truncated, and extended.
+func tamper(proof [][]byte) [][][]byte {
+	var tampered [][][]byte
+
+	for i := range proof {
+		p := make([][]byte, len(proof))
+		copy(p, proof)
+		p[i] = append([]byte{}, proof[i]...)
+		p[i][0] ^= 1
+		tampered = append(tampered, p)
+	}
+
+	if len(proof) > 0 {
+		tampered = append(tampered, proof[:len(proof)-1])
+	}
+
+	return append(tampered, append(append([][]byte{}, proof...), make([]byte, 32)))
+}
+
+func TestMerkleProofs(t *testing.T) {
+	const maxSize = 33
+
+	leaves := make([][]byte, maxSize)
+	for i := range leaves {
+		leaves[i] = []byte{byte(i), byte(i >> 8)}
+	}
+
+	testAll(t, func(h *testHash) {
+		tree := newMerkleTree(t, h.HashID, leaves)
+
+		for size := uint64(1); size <= maxSize; size++ {
+			root, _ := tree.RootAt(size)
+
+			for index := uint64(0); index < size; index++ {
+				proof, err := tree.InclusionProof(index, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				leaf, _ := merkle.LeafHash(h.HashID, leaves[index])
+
+				if err = merkle.VerifyInclusion(h.HashID, index, size, leaf, proof, root); err != nil {
+					t.Fatalf("%d/%d: unexpected error: %v", index, size, err)
+				}
+
+				for _, p := range tamper(proof) {
+					if err = merkle.VerifyInclusion(h.HashID, index, size, leaf, p, root); !errors.Is(
+						err,
+						merkle.ErrInvalidProof,
+					) {
+						t.Fatalf("%d/%d: expected error %q, got %v", index, size, merkle.ErrInvalidProof, err)
+					}
+				}
+			}
+
+			for oldSize := uint64(1); oldSize <= size; oldSize++ {
+				oldRoot, _ := tree.RootAt(oldSize)
+
+				proof, err := tree.ConsistencyProof(oldSize, size)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if err = merkle.VerifyConsistency(h.HashID, oldSize, size, oldRoot, root, proof); err != nil {
+					t.Fatalf("%d/%d: unexpected error: %v", oldSize, size, err)
+				}
+
+				for _, p := range tamper(proof) {
+					if err = merkle.VerifyConsistency(h.HashID, oldSize, size, oldRoot, root, p); !errors.Is(
+						err,
+						merkle.ErrInvalidProof,
+					) {
+						t.Fatalf("%d/%d: expected error %q, got %v", oldSize, size, merkle.ErrInvalidProof, err)
+					}
+				}
+			}
+		}
+	})
+}
+
+func TestMerkleErrors(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	if _, err := merkle.New(0); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if _, err := merkle.LeafHash(0, nil); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	tree := newMerkleTree(t, hash.SHA256, merkleLeaves)
+	size := tree.Size()
+	root := tree.Root()
+
+	if tree.Algorithm() != hash.SHA256 {
+		t.Errorf("unexpected algorithm %s", tree.Algorithm())
+	}
+
+	for _, test := range []struct {
+		err         error
+		index, size uint64
+	}{
+		{merkle.ErrInvalidIndex, size, size},
+		{merkle.ErrInvalidIndex, 0, 0},
+		{merkle.ErrInvalidSize, 0, size + 1},
+	} {
+		if _, err := tree.InclusionProof(test.index, test.size); !errors.Is(err, test.err) {
+			t.Errorf("%d/%d: expected error %q, got %v", test.index, test.size, test.err, err)
+		}
+	}
+
+	for _, sizes := range [][2]uint64{{0, size}, {2, 1}, {1, size + 1}} {
+		if _, err := tree.ConsistencyProof(sizes[0], sizes[1]); !errors.Is(err, merkle.ErrInvalidSize) {
+			t.Errorf("%v: expected error %q, got %v", sizes, merkle.ErrInvalidSize, err)
+		}
+	}
+
+	if err := merkle.VerifyInclusion(hash.SHA256, size, size, root, nil, root); !errors.Is(err, merkle.ErrInvalidIndex) {
+		t.Errorf("expected error %q, got %v", merkle.ErrInvalidIndex, err)
+	}
+
+	if err := merkle.VerifyInclusion(0, 0, size, root, nil, root); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if err := merkle.VerifyConsistency(hash.SHA256, 0, size, root, root, nil); !errors.Is(err, merkle.ErrInvalidSize) {
+		t.Errorf("expected error %q, got %v", merkle.ErrInvalidSize, err)
+	}
+
+	if err := merkle.VerifyConsistency(0, 1, size, root, root, nil); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	oldRoot, _ := tree.RootAt(1)
+
+	for _, test := range []struct {
+		oldRoot, newRoot []byte
+		proof            [][]byte
+		oldSize          uint64
+	}{
+		{oldRoot, root, nil, 1},
+		{root, root, [][]byte{root}, size},
+		{oldRoot, root, nil, size},
+	} {
+		err := merkle.VerifyConsistency(hash.SHA256, test.oldSize, size, test.oldRoot, test.newRoot, test.proof)
+		if !errors.Is(err, merkle.ErrInvalidProof) {
+			t.Errorf("%d: expected error %q, got %v", test.oldSize, merkle.ErrInvalidProof, err)
+		}
+	}
+}
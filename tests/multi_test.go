@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func allHashes() []hash.Hash {
+	hashes := make([]hash.Hash, len(testHashes))
+	for i, h := range testHashes {
+		hashes[i] = h.HashID
+	}
+
+	return hashes
+}
+
+func checkSums(t *testing.T, sums map[hash.Hash][]byte, hashes []hash.Hash, data []byte) {
+	t.Helper()
+
+	if len(sums) != len(hashes) {
+		t.Fatalf("expected %d digests, got %d", len(hashes), len(sums))
+	}
+
+	for _, h := range hashes {
+		if expected := h.Hash(data); !bytes.Equal(sums[h], expected) {
+			t.Fatalf("%s: expected %x, got %x", h, expected, sums[h])
+		}
+	}
+}
+
+func TestMultiHasher(t *testing.T) {
+	hashes := allHashes()
+
+	for name, newMultiHasher := range map[string]func(...hash.Hash) (*hash.MultiHasher, error){
+		"sequential": hash.NewMultiHasher,
+		"parallel":   hash.NewParallelMultiHasher,
+	} {
+		t.Run(name, func(t *testing.T) {
+			m, err := newMultiHasher(hashes...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(m.Hashes(), hashes) {
+				t.Fatalf("expected %v, got %v", hashes, m.Hashes())
+			}
+
+			checkSums(t, m.Sums(), hashes, nil)
+
+			n, err := io.Copy(m, bytes.NewReader(streamData))
+			if err != nil || n != int64(len(streamData)) {
+				t.Fatalf("unexpected copy of %d bytes: %v", n, err)
+			}
+
+			checkSums(t, m.Sums(), hashes, streamData)
+
+			// Sums doesn't change the running hashes.
+			checkSums(t, m.Sums(), hashes, streamData)
+
+			_, _ = m.Write(testData.message)
+			checkSums(t, m.Sums(), hashes, append(append([]byte{}, streamData...), testData.message...))
+
+			m.Reset()
+
+			if _, err = io.ReadAll(io.TeeReader(bytes.NewReader(testData.message), m)); err != nil {
+				t.Fatal(err)
+			}
+
+			checkSums(t, m.Sums(), hashes, testData.message)
+		})
+	}
+}
+
+func TestMultiHasherDuplicates(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHA3_256, hash.SHA512)
+
+	m, err := hash.NewMultiHasher(hash.SHA256, hash.SHA512, hash.SHA256, hash.SHA3_256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []hash.Hash{hash.SHA256, hash.SHA512, hash.SHA3_256}
+	if !slices.Equal(m.Hashes(), expected) {
+		t.Fatalf("expected %v, got %v", expected, m.Hashes())
+	}
+
+	_, _ = m.Write(testData.message)
+	checkSums(t, m.Sums(), expected, testData.message)
+}
+
+func TestMultiHasherUnavailable(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	if _, err := hash.NewMultiHasher(hash.SHA256, 0); !errors.Is(err, hash.ErrUnavailable) {
+		t.Fatalf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if _, err := hash.NewParallelMultiHasher(hash.SHA256, 0); !errors.Is(err, hash.ErrUnavailable) {
+		t.Fatalf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	m, err := hash.NewMultiHasher()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := m.Write(testData.message); n != len(testData.message) || err != nil {
+		t.Fatalf("unexpected write of %d bytes: %v", n, err)
+	}
+
+	if len(m.Sums()) != 0 {
+		t.Fatal("expected no digests")
+	}
+}
+
+func benchmarkMultiHasher(b *testing.B, newMultiHasher func(...hash.Hash) (*hash.MultiHasher, error)) {
+	m, err := newMultiHasher(hash.SHA256, hash.SHA512, hash.SHA3_256)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.SetBytes(int64(len(streamData)))
+	b.ReportAllocs()
+
+	for range b.N {
+		m.Reset()
+		_, _ = io.Copy(m, bytes.NewReader(streamData))
+		_ = m.Sums()
+	}
+}
+
+func BenchmarkMultiHasher(b *testing.B) {
+	benchmarkMultiHasher(b, hash.NewMultiHasher)
+}
+
+func BenchmarkParallelMultiHasher(b *testing.B) {
+	benchmarkMultiHasher(b, hash.NewParallelMultiHasher)
+}
@@ -0,0 +1,239 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bytemare/hash"
+	"github.com/bytemare/hash/multihash"
+)
+
+var multihashVectors = []struct {
+	data      string
+	multihash string
+	hash      hash.Hash
+	length    int
+}{
+	{"multihash", "12209cbc07c3f991725836a3aa2a581ca2029198aa420b9d99bc0e131d9f3e2cbe47", hash.SHA256, 0},
+	{"multihash", "12149cbc07c3f991725836a3aa2a581ca2029198aa42", hash.SHA256, 20},
+	{"multihash", "162008c3792b2a4deed1bd7ea2328fb5de5531eccf0fbfa04a7d800cdc267137c635", hash.SHA3_256, 0},
+	{
+		"multihash",
+		"95202028350009438924cf144110342db8a713f39507cfe828fb66b20b01e147ddb29e",
+		hash.SHA512_256,
+		0,
+	},
+	{
+		"multihash",
+		"c0e4024082477a43d5497a8d5d17b2ef542c81be635ddc383738d9a38c04295387cbda52ec089b58c3036ecb192ed3f5eeec42e4baa" +
+			"4f308e4a14b00a60d296037d1d0f6",
+		hash.BLAKE2B_512,
+		0,
+	},
+	{
+		"multihash",
+		"e0e40220fd819eb9fa98079c54be7da4608acc01a44042bf1ad0e66c95fc27bf43c1a317",
+		hash.BLAKE2S_256,
+		0,
+	},
+	{"multihash", "1820d37045663a07fb35ec571d8f6ef98300a2daa5a82d9d055e684bc292e98a02a3", hash.SHAKE128, 0},
+	{
+		"multihash",
+		"19402a60d18184c0c3aa504e27688378e1fafc23becea2bceb88957be61d44e142506f88462f9624c023a753921571e08a9f2b6b9236" +
+			"eda1e2e35246f76967c5e536",
+		hash.SHAKE256,
+		64,
+	},
+	{"multihash", "19102a60d18184c0c3aa504e27688378e1fa", hash.SHAKE256, 16},
+	{"", "1e20af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262", hash.BLAKE3, 0},
+}
+
+func TestMultihashVectors(t *testing.T) {
+	for i, v := range multihashVectors {
+		if !v.hash.Available() {
+			continue
+		}
+
+		expected, err := hex.DecodeString(v.multihash)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		mh, err := multihash.Sum(v.hash, []byte(v.data), v.length)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		if !bytes.Equal(mh, expected) {
+			t.Fatalf("%d: unexpected multihash\n\twant: %x\n\tgot : %x", i, expected, mh)
+		}
+
+		decoded, err := multihash.Decode(mh)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		code, _ := multihash.Code(v.hash)
+		if decoded.Hash != v.hash || decoded.Code != code {
+			t.Fatalf("%d: unexpected decoded hash function %s (%#x)", i, decoded.Hash, decoded.Code)
+		}
+
+		if reencoded, err := multihash.Encode(decoded.Hash, decoded.Digest); err != nil || !bytes.Equal(reencoded, mh) {
+			t.Fatalf("%d: unexpected re-encoding %x: %v", i, reencoded, err)
+		}
+
+		if err = multihash.Verify(mh, []byte(v.data)); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+
+		if err = multihash.Verify(mh, []byte("other")); !errors.Is(err, multihash.ErrMismatch) {
+			t.Fatalf("%d: expected error %q, got %v", i, multihash.ErrMismatch, err)
+		}
+	}
+}
+
+func TestMultihashCodes(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		code, err := multihash.Code(h.HashID)
+		if h.HashID == hash.BLAKE2XB || h.HashID == hash.BLAKE2XS {
+			if !errors.Is(err, multihash.ErrNoCode) {
+				t.Fatalf("expected error %q, got %v", multihash.ErrNoCode, err)
+			}
+
+			return
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if id, err := multihash.FromCode(code); err != nil || id != h.HashID {
+			t.Fatalf("unexpected hash function %s for code %#x: %v", id, code, err)
+		}
+	})
+
+	if _, err := multihash.FromCode(0x22); !errors.Is(err, multihash.ErrUnknownCode) {
+		t.Errorf("expected error %q, got %v", multihash.ErrUnknownCode, err)
+	}
+
+	if _, err := multihash.Code(0); !errors.Is(err, multihash.ErrNoCode) {
+		t.Errorf("expected error %q, got %v", multihash.ErrNoCode, err)
+	}
+}
+
+// multihashRegistration holds the result of the registration of the custom SHA-1 code, which can't be undone.
+var multihashRegistration struct {
+	err  error
+	once sync.Once
+}
+
+func TestMultihashRegister(t *testing.T) {
+	skipUnavailable(t, hash.BLAKE2XB)
+
+	h, _ := registerCustom(t)
+
+	multihashRegistration.once.Do(func() {
+		multihashRegistration.err = multihash.Register(h, 0x11)
+	})
+
+	if multihashRegistration.err != nil {
+		t.Fatal(multihashRegistration.err)
+	}
+
+	expected := "111488c2f11fb2ce392acb5b2986e640211c4690073e"
+
+	mh, err := multihash.Sum(h, []byte("multihash"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(mh) != expected {
+		t.Fatalf("unexpected multihash %x", mh)
+	}
+
+	for _, test := range []struct {
+		err  error
+		hash hash.Hash
+		code uint64
+	}{
+		{multihash.ErrDuplicateCode, h, 0x1000},
+		{multihash.ErrDuplicateCode, hash.BLAKE2XB, multihash.SHA2_256},
+		{hash.ErrUnavailable, 0, 0x1000},
+	} {
+		if err = multihash.Register(test.hash, test.code); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %q, got %v", test.hash, test.err, err)
+		}
+	}
+}
+
+func TestMultihashErrors(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHAKE128, hash.BLAKE2XB, hash.BLAKE2XS)
+
+	digest := make([]byte, 32)
+
+	if _, err := multihash.Encode(hash.BLAKE2XB, digest); !errors.Is(err, multihash.ErrNoCode) {
+		t.Errorf("expected error %q, got %v", multihash.ErrNoCode, err)
+	}
+
+	if _, err := multihash.Sum(hash.BLAKE2XS, digest, 0); !errors.Is(err, multihash.ErrNoCode) {
+		t.Errorf("expected error %q, got %v", multihash.ErrNoCode, err)
+	}
+
+	for _, test := range []struct {
+		hash   hash.Hash
+		length int
+	}{
+		{hash.SHA256, -1},
+		{hash.SHA256, 33},
+		{hash.SHAKE128, -1},
+	} {
+		if _, err := multihash.Sum(test.hash, digest, test.length); !errors.Is(err, multihash.ErrInvalidLength) {
+			t.Errorf("%s %d: expected error %q, got %v", test.hash, test.length, multihash.ErrInvalidLength, err)
+		}
+	}
+
+	for _, d := range [][]byte{nil, make([]byte, 33)} {
+		if _, err := multihash.Encode(hash.SHA256, d); !errors.Is(err, multihash.ErrInvalidLength) {
+			t.Errorf("%d: expected error %q, got %v", len(d), multihash.ErrInvalidLength, err)
+		}
+	}
+
+	valid, _ := hex.DecodeString("12209cbc07c3f991725836a3aa2a581ca2029198aa420b9d99bc0e131d9f3e2cbe47")
+
+	for _, test := range []struct {
+		err       error
+		multihash string
+	}{
+		{multihash.ErrInvalidMultihash, ""},
+		{multihash.ErrInvalidMultihash, "12"},
+		{multihash.ErrInvalidMultihash, "80"},
+		{multihash.ErrInvalidMultihash, "9200" + hex.EncodeToString(valid[1:])},
+		{multihash.ErrInvalidMultihash, "ffffffffffffffffff01"},
+		{multihash.ErrInvalidMultihash, hex.EncodeToString(valid[:len(valid)-1])},
+		{multihash.ErrInvalidMultihash, hex.EncodeToString(valid) + "00"},
+		{multihash.ErrUnknownCode, "2200"},
+		{multihash.ErrInvalidLength, "1200"},
+		{multihash.ErrInvalidLength, "1221" + hex.EncodeToString(make([]byte, 33))},
+	} {
+		mh, _ := hex.DecodeString(test.multihash)
+
+		if _, err := multihash.Decode(mh); !errors.Is(err, test.err) {
+			t.Errorf("%q: expected error %q, got %v", test.multihash, test.err, err)
+		}
+
+		if err := multihash.Verify(mh, nil); !errors.Is(err, test.err) {
+			t.Errorf("%q: expected error %q, got %v", test.multihash, test.err, err)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+const concurrency = 16
+
+func TestAcquireRelease(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		empty := h.HashID.Hash()
+		expected := h.HashID.Hash(testData.message)
+
+		for range 3 {
+			hasher := h.HashID.Acquire()
+			if hasher.Algorithm() != h.HashID {
+				t.Fatalf("expected %s, got %s", h.HashID, hasher.Algorithm())
+			}
+
+			if sum := hasher.Sum(nil); !bytes.Equal(sum, empty) {
+				t.Fatalf("expected initial state, got %x", sum)
+			}
+
+			hasher.Reset()
+			_, _ = hasher.Write(testData.message)
+
+			if sum := hasher.Sum(nil); !bytes.Equal(sum, expected) {
+				t.Fatalf("expected %x, got %x", expected, sum)
+			}
+
+			h.HashID.Release(hasher)
+		}
+	})
+}
+
+func TestAcquireUnavailable(t *testing.T) {
+	if _, err := hash.Hash(0).AcquireE(); !errors.Is(err, hash.ErrUnavailable) {
+		t.Fatalf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if hasPanic, err := expectPanic(hash.ErrUnavailable, func() {
+		_ = hash.Hash(0).Acquire()
+	}); !hasPanic {
+		t.Fatalf("expected panic: %v", err)
+	}
+
+	hash.SHA256.Release(nil)
+}
+
+func TestReleaseForeign(t *testing.T) {
+	skipUnavailable(t, hash.BLAKE2B_256, hash.SHA256, hash.SHA512)
+
+	key, _ := hex.DecodeString(testData.key[32])
+	empty := hash.BLAKE2B_256.Hash()
+
+	keyed, err := hash.BLAKE2B_256.NewKeyed(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyedEmpty := keyed.Sum(nil)
+	_, _ = keyed.Write(testData.message)
+
+	// Released keyed Hashers are reset, but never handed to other callers.
+	hash.BLAKE2B_256.Release(keyed)
+
+	if sum := keyed.Sum(nil); !bytes.Equal(sum, keyedEmpty) {
+		t.Fatalf("expected the keyed Hasher to be reset, got %x", sum)
+	}
+
+	if sum := hash.BLAKE2B_256.Acquire().Sum(nil); !bytes.Equal(sum, empty) {
+		t.Fatalf("a keyed Hasher leaked into the pool: %x", sum)
+	}
+
+	hash.SHA512.Release(hash.SHA256.New())
+
+	if a := hash.SHA512.Acquire().Algorithm(); a != hash.SHA512 {
+		t.Fatalf("a %s Hasher leaked into the pool of %s", a, hash.SHA512)
+	}
+
+	hash.SHA256.Release(hash.SHA256.NewSafe())
+}
+
+func TestAcquireAllocations(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		dst := make([]byte, h.outputsize)
+		allocs := testing.AllocsPerRun(100, func() {
+			hasher := h.HashID.Acquire()
+			_, _ = hasher.Write(testData.message)
+			_ = hasher.(sumIntoHasher).SumInto(dst)
+			h.HashID.Release(hasher)
+		})
+
+		if allocs != 0 {
+			t.Errorf("expected no allocations, got %v", allocs)
+		}
+	})
+}
+
+func TestAcquireConcurrent(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		expected := h.HashID.Hash(testData.message)
+
+		var wg sync.WaitGroup
+
+		errs := make(chan []byte, concurrency)
+
+		for range concurrency {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for range 100 {
+					hasher := h.HashID.Acquire()
+					_, _ = hasher.Write(testData.message)
+
+					if sum := hasher.Sum(nil); !bytes.Equal(sum, expected) {
+						errs <- sum
+						return
+					}
+
+					h.HashID.Release(hasher)
+				}
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for sum := range errs {
+			t.Fatalf("expected %x, got %x", expected, sum)
+		}
+	})
+}
+
+func TestSafeHasher(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		s := h.HashID.NewSafe()
+
+		if s.Algorithm() != h.HashID || s.Size() != h.outputsize || s.BlockSize() != h.blocksize {
+			t.Fatal("unexpected metadata")
+		}
+
+		if (s.GetHashFunction() != nil) != (h.HashType == hash.FixedOutputLength) ||
+			(s.GetXOF() != nil) != (h.HashType == hash.ExtendableOutputFunction) {
+			t.Fatal("unexpected underlying Hasher")
+		}
+
+		// Concurrent writes of the same chunk are interleaved, but the result doesn't depend on the order.
+		var wg sync.WaitGroup
+
+		for range concurrency {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				_, _ = s.Write(testData.message)
+			}()
+		}
+
+		wg.Wait()
+
+		expected := h.HashID.Hash(bytes.Repeat(testData.message, concurrency))
+
+		c := s.Clone()
+
+		clone, ok := c.(*hash.SafeHasher)
+		if !ok {
+			t.Fatalf("expected a SafeHasher clone, got %T", c)
+		}
+
+		if sum := s.Sum(nil); !bytes.Equal(sum, expected) {
+			t.Fatalf("expected %x, got %x", expected, sum)
+		}
+
+		if sum := clone.SumInto(make([]byte, h.outputsize)); !bytes.Equal(sum, expected) {
+			t.Fatalf("expected %x from the clone, got %x", expected, sum)
+		}
+
+		s.Reset()
+
+		if sum := s.Read(h.outputsize); !bytes.Equal(sum, h.HashID.Hash()) {
+			t.Fatalf("expected initial state, got %x", sum)
+		}
+	})
+}
+
+func TestSafeHasherConcurrentHash(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		s := hash.NewSafeHasher(h.HashID.New())
+		expected := h.HashID.Hash(testData.message, testData.secret)
+
+		var wg sync.WaitGroup
+
+		errs := make(chan []byte, 2*concurrency)
+
+		for range concurrency {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				dst := make([]byte, h.outputsize)
+
+				for range 100 {
+					if sum := s.HashInto(dst, testData.message, testData.secret); !bytes.Equal(sum, expected) {
+						errs <- sum
+						return
+					}
+
+					sum := s.Hash(uint(h.outputsize), testData.message, testData.secret)
+					if !bytes.Equal(sum, expected) {
+						errs <- sum
+						return
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for sum := range errs {
+			t.Fatalf("expected %x, got %x", expected, sum)
+		}
+
+		if hasPanic, err := expectPanic(hash.ErrSmallOutputSize, func() {
+			_ = s.HashInto(make([]byte, h.outputsize-1))
+		}); !hasPanic {
+			t.Fatalf("expected panic: %v", err)
+		}
+	})
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	stdhash "hash"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/sha3"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	customFixedName = "Custom-SHA-1"
+	customXOFName   = "Custom-cSHAKE128"
+)
+
+func newCustomXOF() hash.XOF {
+	return sha3.NewCShake128([]byte("custom"), nil)
+}
+
+// custom holds the identifiers of the custom hash functions. Registrations can't be undone, so they are done once for
+// all tests and all runs of the test binary, e.g. with -count or -shuffle.
+var custom struct {
+	err   error
+	once  sync.Once
+	fixed hash.Hash
+	xof   hash.Hash
+}
+
+// registerCustom returns the identifiers of the custom SHA-1 and cSHAKE128 hash functions, registering them once.
+func registerCustom(t *testing.T) (fixed, xof hash.Hash) {
+	t.Helper()
+
+	custom.once.Do(func() {
+		custom.fixed, custom.err = hash.Register(
+			sha1.New,
+			customFixedName,
+			hash.FixedOutputLength,
+			sha1.BlockSize,
+			sha1.Size,
+			80,
+		)
+		if custom.err != nil {
+			return
+		}
+
+		custom.xof, custom.err = hash.Register(newCustomXOF, customXOFName, hash.ExtendableOutputFunction, 168, 32, 128)
+	})
+
+	if custom.err != nil {
+		t.Fatalf("unexpected error: %v", custom.err)
+	}
+
+	return custom.fixed, custom.xof
+}
+
+func TestRegisterFixed(t *testing.T) {
+	id, _ := registerCustom(t)
+
+	if !id.Available() || id.Type() != hash.FixedOutputLength || id.String() != customFixedName {
+		t.Fatalf("registered hash function %d has invalid metadata", id)
+	}
+
+	if id.Size() != sha1.Size || id.BlockSize() != sha1.BlockSize || id.SecurityLevel() != 80 {
+		t.Fatalf("registered hash function %d has invalid metadata", id)
+	}
+
+	if id.New().Algorithm() != id {
+		t.Fatalf(fmtExpectedEquality, id)
+	}
+
+	expected := sha1.Sum(testData.message)
+	if !bytes.Equal(id.Hash(testData.message), expected[:]) {
+		t.Fatalf(fmtExpectedEquality, id)
+	}
+
+	key := []byte("key")
+	mac := hmac.New(sha1.New, key)
+	_, _ = mac.Write(testData.message)
+
+	if !bytes.Equal(id.GetHashFunction().Hmac(testData.message, key), mac.Sum(nil)) {
+		t.Fatalf(fmtExpectedEquality, id)
+	}
+
+	if id.GetXOF() != nil {
+		t.Fatal("expected pointer to be nil")
+	}
+}
+
+func TestRegisterXOF(t *testing.T) {
+	_, id := registerCustom(t)
+
+	if !id.Available() || id.Type() != hash.ExtendableOutputFunction || id.String() != customXOFName {
+		t.Fatalf("registered hash function %d has invalid metadata", id)
+	}
+
+	expected := make([]byte, 64)
+	x := newCustomXOF()
+	_, _ = x.Write(testData.message)
+	_, _ = x.Read(expected)
+
+	if !bytes.Equal(id.Hash(testData.message), expected[:32]) {
+		t.Fatalf(fmtExpectedEquality, id)
+	}
+
+	if !bytes.Equal(id.New().Hash(64, testData.message), expected) {
+		t.Fatalf(fmtExpectedEquality, id)
+	}
+
+	if id.GetHashFunction() != nil {
+		t.Fatal("expected pointer to be nil")
+	}
+}
+
+func TestRegisterDuplicateName(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHAKE128)
+
+	for _, name := range []string{hash.SHA256.String(), "sha-256", shake128} {
+		if _, err := hash.Register(sha1.New, name, hash.FixedOutputLength, 64, 20, 80); !errors.Is(
+			err,
+			hash.ErrDuplicateName,
+		) {
+			t.Errorf("%s: expected error %q, got %v", name, hash.ErrDuplicateName, err)
+		}
+	}
+}
+
+func TestRegisterInvalid(t *testing.T) {
+	var nilConstructor func() stdhash.Hash
+
+	tests := map[string]func() (hash.Hash, error){
+		"empty name": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "", hash.FixedOutputLength, 64, 20, 80)
+		},
+		"nil constructor": func() (hash.Hash, error) {
+			return hash.Register(nilConstructor, "nil", hash.FixedOutputLength, 64, 20, 80)
+		},
+		"fixed with xof type": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "fixed", hash.ExtendableOutputFunction, 64, 20, 80)
+		},
+		"xof with fixed type": func() (hash.Hash, error) {
+			return hash.Register(newCustomXOF, "xof", hash.FixedOutputLength, 168, 32, 128)
+		},
+		"negative block size": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "block", hash.FixedOutputLength, -1, 20, 80)
+		},
+		"zero output size": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "output", hash.FixedOutputLength, 64, 0, 80)
+		},
+		"output size mismatch": func() (hash.Hash, error) {
+			return hash.Register(sha256.New, "truncated", hash.FixedOutputLength, sha256.BlockSize, 16, 128)
+		},
+		"block size mismatch": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "block mismatch", hash.FixedOutputLength, 128, 20, 80)
+		},
+		"xof block size mismatch": func() (hash.Hash, error) {
+			return hash.Register(newCustomXOF, "xof block mismatch", hash.ExtendableOutputFunction, 136, 32, 128)
+		},
+		"nil instance": func() (hash.Hash, error) {
+			return hash.Register(func() stdhash.Hash { return nil }, "nil instance", hash.FixedOutputLength, 64, 20, 80)
+		},
+		"zero security level": func() (hash.Hash, error) {
+			return hash.Register(sha1.New, "security", hash.FixedOutputLength, 64, 20, 0)
+		},
+	}
+
+	for name, register := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := register(); !errors.Is(err, hash.ErrInvalidRegistration) {
+				t.Errorf("expected error %q, got %v", hash.ErrInvalidRegistration, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func TestSelfTest(t *testing.T) {
+	id, _ := registerCustom(t)
+
+	report := hash.SelfTest()
+	if !report.OK() || report.Err() != nil || len(report.Failures) != 0 {
+		t.Fatalf("unexpected failures: %v", report.Err())
+	}
+
+	for _, h := range testHashes {
+		if !slices.Contains(report.Passed, h.HashID) {
+			t.Errorf("%s: expected to pass", h.name)
+		}
+
+		if slices.Contains(report.Skipped, h.HashID) {
+			t.Errorf("%s: unexpectedly skipped", h.name)
+		}
+	}
+
+	if !slices.Contains(report.Skipped, id) || slices.Contains(report.Passed, id) {
+		t.Fatal("expected the registered function to be skipped")
+	}
+}
+
+func TestSelfTestReportErr(t *testing.T) {
+	skipUnavailable(t, hash.SHA256)
+
+	failure := &hash.SelfTestFailure{
+		Test:     "digest",
+		Expected: []byte{0x01, 0x02},
+		Got:      []byte{0x01, 0x03},
+		Hash:     hash.SHA256,
+	}
+	report := &hash.SelfTestReport{Failures: []*hash.SelfTestFailure{failure}}
+
+	if report.OK() {
+		t.Fatal("expected a failed report")
+	}
+
+	err := report.Err()
+	if !errors.Is(err, hash.ErrSelfTest) {
+		t.Fatalf("expected error %q, got %v", hash.ErrSelfTest, err)
+	}
+
+	var f *hash.SelfTestFailure
+	if !errors.As(err, &f) || f != failure {
+		t.Fatalf("expected the failure to be wrapped, got %v", err)
+	}
+
+	if expected := "SHA-256 digest: expected 0102, got 0103"; !strings.Contains(err.Error(), expected) {
+		t.Fatalf("expected %q in %q", expected, err.Error())
+	}
+}
@@ -0,0 +1,458 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+//go:build !hash_nosha3
+
+package tests_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+	"github.com/bytemare/hash/hashtest"
+)
+
+// The following vectors are the NIST SP 800-185 samples, from
+// https://csrc.nist.gov/projects/cryptographic-standards-and-guidelines/example-values.
+
+type sp800185Vector struct {
+	name          string
+	customization string
+	output        string
+	data          []byte
+	hash          hash.Hash
+}
+
+func sequence(length int) []byte {
+	s := make([]byte, length)
+	for i := range s {
+		s[i] = byte(i)
+	}
+
+	return s
+}
+
+func sp800185Key() []byte {
+	key := make([]byte, 32)
+	for i := range key {
+		key[i] = byte(0x40 + i)
+	}
+
+	return key
+}
+
+var (
+	tuple1 = [][]byte{{0x00, 0x01, 0x02}, {0x10, 0x11, 0x12, 0x13, 0x14, 0x15}}
+	tuple2 = [][]byte{
+		{0x00, 0x01, 0x02},
+		{0x10, 0x11, 0x12, 0x13, 0x14, 0x15},
+		{0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27, 0x28},
+	}
+	parallelInput = []byte{
+		0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17,
+		0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27,
+	}
+)
+
+var cshakeVectors = []sp800185Vector{
+	{
+		"cSHAKE128 sample #2", "Email Signature",
+		"c1c36925b6409a04f1b504fcbca9d82b4017277cb5ed2b2065fc1d3814d5aaf5",
+		sequence(4), hash.SHAKE128,
+	},
+	{
+		"cSHAKE256 sample #3", "Email Signature",
+		"d008828e2b80ac9d2218ffee1d070c48b8e4c87bff32c9699d5b6896eee0edd1" +
+			"64020e2be0560858d9c00c037e34a96937c561a74c412bb4c746469527281c8c",
+		sequence(4), hash.SHAKE256,
+	},
+}
+
+var kmacVectors = []sp800185Vector{
+	{
+		"KMAC128 sample #1", "",
+		"e5780b0d3ea6f7d3a429c5706aa43a00fadbd7d49628839e3187243f456ee14e",
+		sequence(4), hash.SHAKE128,
+	},
+	{
+		"KMAC128 sample #2", "My Tagged Application",
+		"3b1fba963cd8b0b59e8c1a6d71888b7143651af8ba0a7070c0979e2811324aa5",
+		sequence(4), hash.SHAKE128,
+	},
+	{
+		"KMAC128 sample #3", "My Tagged Application",
+		"1f5b4e6cca02209e0dcb5ca635b89a15e271ecc760071dfd805faa38f9729230",
+		sequence(200), hash.SHAKE128,
+	},
+	{
+		"KMAC256 sample #4", "My Tagged Application",
+		"20c570c31346f703c9ac36c61c03cb64c3970d0cfc787e9b79599d273a68d2f7" +
+			"f69d4cc3de9d104a351689f27cf6f5951f0103f33f4f24871024d9c27773a8dd",
+		sequence(4), hash.SHAKE256,
+	},
+	{
+		"KMAC256 sample #5", "",
+		"75358cf39e41494e949707927cee0af20a3ff553904c86b08f21cc414bcfd691" +
+			"589d27cf5e15369cbbff8b9a4c2eb17800855d0235ff635da82533ec6b759b69",
+		sequence(200), hash.SHAKE256,
+	},
+	{
+		"KMAC256 sample #6", "My Tagged Application",
+		"b58618f71f92e1d56c1b8c55ddd7cd188b97b4ca4d99831eb2699a837da2e4d9" +
+			"70fbacfde50033aea585f1a2708510c32d07880801bd182898fe476876fc8965",
+		sequence(200), hash.SHAKE256,
+	},
+}
+
+var kmacXOFVectors = []sp800185Vector{
+	{
+		"KMACXOF128 sample #1", "",
+		"cd83740bbd92ccc8cf032b1481a0f4460e7ca9dd12b08a0c4031178bacd6ec35",
+		sequence(4), hash.SHAKE128,
+	},
+	{
+		"KMACXOF128 sample #2", "My Tagged Application",
+		"31a44527b4ed9f5c6101d11de6d26f0620aa5c341def41299657fe9df1a3b16c",
+		sequence(4), hash.SHAKE128,
+	},
+	{
+		"KMACXOF128 sample #3", "My Tagged Application",
+		"47026c7cd793084aa0283c253ef658490c0db61438b8326fe9bddf281b83ae0f",
+		sequence(200), hash.SHAKE128,
+	},
+	{
+		"KMACXOF256 sample #4", "My Tagged Application",
+		"1755133f1534752aad0748f2c706fb5c784512cab835cd15676b16c0c6647fa9" +
+			"6faa7af634a0bf8ff6df39374fa00fad9a39e322a7c92065a64eb1fb0801eb2b",
+		sequence(4), hash.SHAKE256,
+	},
+	{
+		"KMACXOF256 sample #5", "",
+		"ff7b171f1e8a2b24683eed37830ee797538ba8dc563f6da1e667391a75edc02c" +
+			"a633079f81ce12a25f45615ec89972031d18337331d24ceb8f8ca8e6a19fd98b",
+		sequence(200), hash.SHAKE256,
+	},
+	{
+		"KMACXOF256 sample #6", "My Tagged Application",
+		"d5be731c954ed7732846bb59dbe3a8e30f83e77a4bff4459f2f1c2b4ecebb8ce" +
+			"67ba01c62e8ab8578d2d499bd1bb276768781190020a306a97de281dcc30305d",
+		sequence(200), hash.SHAKE256,
+	},
+}
+
+type tupleHashVector struct {
+	name          string
+	customization string
+	output        string
+	tuple         [][]byte
+	hash          hash.Hash
+	xof           bool
+}
+
+var tupleHashVectors = []tupleHashVector{
+	{
+		"TupleHash128 sample #1", "",
+		"c5d8786c1afb9b82111ab34b65b2c0048fa64e6d48e263264ce1707d3ffc8ed1",
+		tuple1, hash.SHAKE128, false,
+	},
+	{
+		"TupleHash128 sample #2", "My Tuple App",
+		"75cdb20ff4db1154e841d758e24160c54bae86eb8c13e7f5f40eb35588e96dfb",
+		tuple1, hash.SHAKE128, false,
+	},
+	{
+		"TupleHash128 sample #3", "My Tuple App",
+		"e60f202c89a2631eda8d4c588ca5fd07f39e5151998deccf973adb3804bb6e84",
+		tuple2, hash.SHAKE128, false,
+	},
+	{
+		"TupleHash256 sample #4", "",
+		"cfb7058caca5e668f81a12a20a2195ce97a925f1dba3e7449a56f82201ec6073" +
+			"11ac2696b1ab5ea2352df1423bde7bd4bb78c9aed1a853c78672f9eb23bbe194",
+		tuple1, hash.SHAKE256, false,
+	},
+	{
+		"TupleHash256 sample #5", "My Tuple App",
+		"147c2191d5ed7efd98dbd96d7ab5a11692576f5fe2a5065f3e33de6bba9f3aa1" +
+			"c4e9a068a289c61c95aab30aee1e410b0b607de3620e24a4e3bf9852a1d4367e",
+		tuple1, hash.SHAKE256, false,
+	},
+	{
+		"TupleHash256 sample #6", "My Tuple App",
+		"45000be63f9b6bfd89f54717670f69a9bc763591a4f05c50d68891a744bcc6e7" +
+			"d6d5b5e82c018da999ed35b0bb49c9678e526abd8e85c13ed254021db9e790ce",
+		tuple2, hash.SHAKE256, false,
+	},
+	{
+		"TupleHashXOF128 sample #1", "",
+		"2f103cd7c32320353495c68de1a8129245c6325f6f2a3d608d92179c96e68488",
+		tuple1, hash.SHAKE128, true,
+	},
+	{
+		"TupleHashXOF256 sample #4", "",
+		"03ded4610ed6450a1e3f8bc44951d14fbc384ab0efe57b000df6b6df5aae7cd5" +
+			"68e77377daf13f37ec75cf5fc598b6841d51dd207c991cd45d210ba60ac52eb9",
+		tuple1, hash.SHAKE256, true,
+	},
+}
+
+var parallelHashVectors = []tupleHashVector{
+	{
+		"ParallelHash128 sample #1", "",
+		"ba8dc1d1d979331d3f813603c67f72609ab5e44b94a0b8f9af46514454a2b4f5",
+		nil, hash.SHAKE128, false,
+	},
+	{
+		"ParallelHash128 sample #2", "Parallel Data",
+		"fc484dcb3f84dceedc353438151bee58157d6efed0445a81f165e495795b7206",
+		nil, hash.SHAKE128, false,
+	},
+	{
+		"ParallelHash256 sample #4", "",
+		"bc1ef124da34495e948ead207dd9842235da432d2bbc54b4c110e64c45110553" +
+			"1b7f2a3e0ce055c02805e7c2de1fb746af97a1dd01f43b824e31b87612410429",
+		nil, hash.SHAKE256, false,
+	},
+	{
+		"ParallelHash256 sample #5", "Parallel Data",
+		"cdf15289b54f6212b4bc270528b49526006dd9b54e2b6add1ef6900dda3963bb" +
+			"33a72491f236969ca8afaea29c682d47a393c065b38e29fae651a2091c833110",
+		nil, hash.SHAKE256, false,
+	},
+	{
+		"ParallelHashXOF128 sample #1", "",
+		"fe47d661e49ffe5b7d999922c062356750caf552985b8e8ce6667f2727c3c8d3",
+		nil, hash.SHAKE128, true,
+	},
+	{
+		"ParallelHashXOF256 sample #4", "",
+		"c10a052722614684144d28474850b410757e3cba87651ba167a5cbddff7f4666" +
+			"75fbf84bcae7378ac444be681d729499afca667fb879348bfdda427863c82f1c",
+		nil, hash.SHAKE256, true,
+	},
+}
+
+func TestCSHAKE(t *testing.T) {
+	for _, v := range cshakeVectors {
+		t.Run(v.name, func(t *testing.T) {
+			expected := decodeHex(t, v.output)
+
+			c, err := v.hash.NewCSHAKE(nil, []byte(v.customization))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if output := c.Hash(uint(len(expected)), v.data); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+		})
+	}
+
+	// With empty function name and customization strings, cSHAKE is SHAKE.
+	for _, h := range []hash.Hash{hash.SHAKE128, hash.SHAKE256} {
+		c, _ := h.NewCSHAKE(nil, nil)
+		if !bytes.Equal(c.Hash(uint(h.Size()), testData.message), h.Hash(testData.message)) {
+			t.Errorf(fmtExpectedEquality, h)
+		}
+	}
+}
+
+func TestKMAC(t *testing.T) {
+	for _, v := range kmacVectors {
+		t.Run(v.name, func(t *testing.T) {
+			expected := decodeHex(t, v.output)
+
+			mac, err := v.hash.NewKMAC(sp800185Key(), []byte(v.customization), len(expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if mac.Size() != len(expected) {
+				t.Fatalf("invalid size %d", mac.Size())
+			}
+
+			_, _ = mac.Write(v.data)
+
+			// Sum must not modify the state.
+			if output := mac.Sum(nil); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+
+			if output := mac.Sum(nil); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+
+			mac.Reset()
+			_, _ = mac.Write(v.data)
+
+			if output := mac.Sum(nil); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output after reset\n\twant: %x\n\tgot : %x", expected, output)
+			}
+		})
+	}
+}
+
+func TestKMACXOF(t *testing.T) {
+	for _, v := range kmacXOFVectors {
+		t.Run(v.name, func(t *testing.T) {
+			expected := decodeHex(t, v.output)
+
+			x, err := v.hash.NewKMACXOF(sp800185Key(), []byte(v.customization))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if output := x.Hash(uint(len(expected)), v.data); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+
+			// Hash resets the state, which must retain the key.
+			if output := x.Hash(uint(len(expected)), v.data); !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output after reset\n\twant: %x\n\tgot : %x", expected, output)
+			}
+		})
+	}
+}
+
+func TestTupleHash(t *testing.T) {
+	for _, v := range tupleHashVectors {
+		t.Run(v.name, func(t *testing.T) {
+			expected := decodeHex(t, v.output)
+			f := v.hash.TupleHash
+
+			if v.xof {
+				f = v.hash.TupleHashXOF
+			}
+
+			output, err := f(v.tuple, []byte(v.customization), len(expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+		})
+	}
+}
+
+func TestParallelHash(t *testing.T) {
+	for _, v := range parallelHashVectors {
+		t.Run(v.name, func(t *testing.T) {
+			expected := decodeHex(t, v.output)
+			f := v.hash.ParallelHash
+
+			if v.xof {
+				f = v.hash.ParallelHashXOF
+			}
+
+			output, err := f(parallelInput, 8, []byte(v.customization), len(expected))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(output, expected) {
+				t.Fatalf("unexpected output\n\twant: %x\n\tgot : %x", expected, output)
+			}
+		})
+	}
+}
+
+func TestSP800185Errors(t *testing.T) {
+	key := sp800185Key()
+
+	if _, err := hash.SHA256.NewCSHAKE(nil, nil); !errors.Is(err, hash.ErrNotSHAKE) {
+		t.Errorf("expected error %q, got %v", hash.ErrNotSHAKE, err)
+	}
+
+	if _, err := hash.BLAKE2XB.NewKMAC(key, nil, 32); !errors.Is(err, hash.ErrNotSHAKE) {
+		t.Errorf("expected error %q, got %v", hash.ErrNotSHAKE, err)
+	}
+
+	if _, err := hash.SHA3_256.NewKMACXOF(key, nil); !errors.Is(err, hash.ErrNotSHAKE) {
+		t.Errorf("expected error %q, got %v", hash.ErrNotSHAKE, err)
+	}
+
+	if _, err := hash.SHAKE128.NewKMAC(key, nil, 16); !errors.Is(err, hash.ErrSmallOutputSize) {
+		t.Errorf("expected error %q, got %v", hash.ErrSmallOutputSize, err)
+	}
+
+	if _, err := hash.SHAKE256.TupleHash(tuple1, nil, 1); !errors.Is(err, hash.ErrSmallOutputSize) {
+		t.Errorf("expected error %q, got %v", hash.ErrSmallOutputSize, err)
+	}
+
+	if _, err := hash.SHA512.TupleHashXOF(tuple1, nil, 64); !errors.Is(err, hash.ErrNotSHAKE) {
+		t.Errorf("expected error %q, got %v", hash.ErrNotSHAKE, err)
+	}
+
+	if _, err := hash.SHAKE128.ParallelHash(parallelInput, 0, nil, 32); !errors.Is(err, hash.ErrBlockSize) {
+		t.Errorf("expected error %q, got %v", hash.ErrBlockSize, err)
+	}
+
+	if _, err := hash.BLAKE3.ParallelHashXOF(parallelInput, 8, nil, 32); !errors.Is(err, hash.ErrNotSHAKE) {
+		t.Errorf("expected error %q, got %v", hash.ErrNotSHAKE, err)
+	}
+}
+
+func TestReleaseCSHAKE(t *testing.T) {
+	cshake, err := hash.SHAKE128.NewCSHAKE(nil, []byte("customization"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Released customized Hashers are never handed to other callers.
+	hash.SHAKE128.Release(cshake)
+
+	if sum := hash.SHAKE128.Acquire().Sum(nil); !bytes.Equal(sum, hash.SHAKE128.Hash()) {
+		t.Fatalf("a cSHAKE Hasher leaked into the pool: %x", sum)
+	}
+}
+
+func TestCloneKMACXOF(t *testing.T) {
+	k, err := hash.SHAKE256.NewKMACXOF(sp800185Key(), []byte("custom"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = k.Write(testData.message)
+	clone := k.Clone()
+	_, _ = k.Write(testData.secret)
+
+	expected, _ := hash.SHAKE256.NewKMACXOF(sp800185Key(), []byte("custom"))
+
+	if !bytes.Equal(clone.Read(64), expected.Hash(64, testData.message)) {
+		t.Fatal("KMACXOF clone diverged")
+	}
+}
+
+func TestConformanceSP800185(t *testing.T) {
+	t.Run("cSHAKE", func(t *testing.T) {
+		hashtest.TestHasher(t, func() hash.Hasher {
+			h, err := hash.SHAKE128.NewCSHAKE(nil, []byte("customization"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return h
+		})
+	})
+
+	t.Run("KMACXOF", func(t *testing.T) {
+		hashtest.TestHasher(t, func() hash.Hasher {
+			h, err := hash.SHAKE256.NewKMACXOF(sp800185Key(), []byte("customization"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return h
+		})
+	})
+}
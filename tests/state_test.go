@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// notMarshalable lists the functions whose underlying implementation does not support state serialization.
+var notMarshalable = map[hash.Hash]bool{
+	hash.SHA3_224: true,
+	hash.SHA3_256: true,
+	hash.SHA3_384: true,
+	hash.SHA3_512: true,
+	hash.SHAKE128: true,
+	hash.SHAKE256: true,
+	hash.BLAKE2XB: true,
+	hash.BLAKE2XS: true,
+}
+
+type marshalingHasher interface {
+	hash.Hasher
+	encoding.BinaryMarshaler
+	encoding.BinaryUnmarshaler
+}
+
+func newMarshalingHasher(h hash.Hash) marshalingHasher {
+	if h.Type() == hash.FixedOutputLength {
+		return h.GetHashFunction()
+	}
+
+	return h.GetXOF()
+}
+
+func TestMarshalBinary(t *testing.T) {
+	input := bytes.Repeat(testData.message, 200)
+	half := len(input) / 2
+
+	testAll(t, func(h *testHash) {
+		hasher := newMarshalingHasher(h.HashID)
+		_, _ = hasher.Write(input[:half])
+
+		state, err := hasher.MarshalBinary()
+		if notMarshalable[h.HashID] {
+			if !errors.Is(err, hash.ErrNotMarshalable) {
+				t.Fatalf("expected error %q, got %v", hash.ErrNotMarshalable, err)
+			}
+
+			header := append([]byte{2, byte(h.HashID), byte(len(h.HashID.String()))}, h.HashID.String()...)
+			if err = newMarshalingHasher(h.HashID).UnmarshalBinary(header); !errors.Is(err, hash.ErrNotMarshalable) {
+				t.Fatalf("expected error %q, got %v", hash.ErrNotMarshalable, err)
+			}
+
+			return
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		restored := newMarshalingHasher(h.HashID)
+		if err = restored.UnmarshalBinary(state); err != nil {
+			t.Fatal(err)
+		}
+
+		_, _ = restored.Write(input[half:])
+
+		if !bytes.Equal(restored.Read(h.outputsize), h.HashID.Hash(input)) {
+			t.Fatalf(fmtExpectedEquality, h.name)
+		}
+	})
+}
+
+func TestUnmarshalBinaryZeroValue(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.BLAKE3)
+
+	fixed := hash.SHA256.GetHashFunction()
+	_, _ = fixed.Write(testData.message)
+
+	state, err := fixed.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restoredFixed := new(hash.Fixed)
+	if err = restoredFixed.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if restoredFixed.Algorithm() != hash.SHA256 || !bytes.Equal(restoredFixed.Sum(nil), fixed.Sum(nil)) {
+		t.Fatal("unexpected restored state")
+	}
+
+	if err = new(hash.ExtendableHash).UnmarshalBinary(state); !errors.Is(err, hash.ErrStateMismatch) {
+		t.Fatalf("expected error %q, got %v", hash.ErrStateMismatch, err)
+	}
+
+	xof := hash.BLAKE3.GetXOF()
+	_, _ = xof.Write(testData.message)
+
+	if state, err = xof.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+
+	restoredXOF := new(hash.ExtendableHash)
+	if err = restoredXOF.UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+
+	if restoredXOF.Algorithm() != hash.BLAKE3 || !bytes.Equal(restoredXOF.Read(64), xof.Read(64)) {
+		t.Fatal("unexpected restored state")
+	}
+
+	if err = new(hash.Fixed).UnmarshalBinary(state); !errors.Is(err, hash.ErrStateMismatch) {
+		t.Fatalf("expected error %q, got %v", hash.ErrStateMismatch, err)
+	}
+}
+
+func TestMarshalBinaryKeyed(t *testing.T) {
+	for _, id := range []hash.Hash{hash.BLAKE2B_256, hash.BLAKE2S_256, hash.BLAKE3} {
+		if !id.Available() {
+			continue
+		}
+
+		h, err := id.NewKeyed(bytes.Repeat([]byte{1}, 32))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = h.(encoding.BinaryMarshaler).MarshalBinary(); !errors.Is(err, hash.ErrNotMarshalable) {
+			t.Fatalf("%s: expected error %q, got %v", id, hash.ErrNotMarshalable, err)
+		}
+	}
+}
+
+func TestUnmarshalBinaryErrors(t *testing.T) {
+	skipUnavailable(t, hash.SHA256, hash.SHA512, hash.BLAKE3)
+
+	h := hash.SHA256.GetHashFunction()
+
+	state, err := h.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidVersion := append([]byte{}, state...)
+	invalidVersion[0]++
+
+	mismatch, err := hash.SHA512.GetHashFunction().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	unavailable := append([]byte{}, state...)
+	unavailable[1] = 0
+
+	// The identifier of another function, with the canonical name of SHA-256.
+	renumbered := append([]byte{}, state...)
+	renumbered[1] = byte(hash.SHA512)
+
+	// The canonical name follows the version, identifier, and name length.
+	renamed := append([]byte{}, state...)
+	renamed[3]++
+
+	for _, test := range []struct {
+		err   error
+		name  string
+		state []byte
+	}{
+		{hash.ErrInvalidState, "empty", nil},
+		{hash.ErrInvalidState, "header only", state[:1]},
+		{hash.ErrInvalidState, "version", invalidVersion},
+		{hash.ErrInvalidState, "truncated", state[:len(state)-1]},
+		{hash.ErrInvalidState, "truncated name", state[:4]},
+		{hash.ErrStateMismatch, "mismatch", mismatch},
+		{hash.ErrStateMismatch, "unavailable", unavailable},
+		{hash.ErrStateMismatch, "renumbered", renumbered},
+		{hash.ErrStateMismatch, "renamed", renamed},
+	} {
+		if err = h.UnmarshalBinary(test.state); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %q, got %v", test.name, test.err, err)
+		}
+
+		// A zero value adopts the recorded hash function, so only mismatch doesn't apply.
+		if test.name == "mismatch" {
+			continue
+		}
+
+		if err = new(hash.Fixed).UnmarshalBinary(test.state); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected error %q on zero value, got %v", test.name, test.err, err)
+		}
+	}
+
+	xof := hash.BLAKE3.GetXOF()
+
+	if state, err = xof.MarshalBinary(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Skip the envelope header, and its canonical name.
+	header := 3 + len(hash.BLAKE3.String())
+
+	for _, corrupt := range [][]byte{
+		state[:len(state)-1],
+		append(state, 0),
+		append(state[:header:header], state[header+1:]...),
+	} {
+		if err = xof.UnmarshalBinary(corrupt); !errors.Is(err, hash.ErrInvalidState) {
+			t.Errorf("expected error %q, got %v", hash.ErrInvalidState, err)
+		}
+	}
+}
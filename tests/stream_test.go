@@ -0,0 +1,173 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bytemare/hash"
+)
+
+var errRead = errors.New("read error")
+
+// streamData spans multiple chunks of the streaming buffer, and ends with a partial one.
+var streamData = bytes.Repeat([]byte("0123456789abcdef"), 10000)
+
+// cancelingReader cancels its context after the first read.
+type cancelingReader struct {
+	io.Reader
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	defer r.cancel()
+	return r.Reader.Read(p)
+}
+
+func streamSize(h *testHash) uint {
+	if h.HashType == hash.ExtendableOutputFunction {
+		return uint(2 * h.outputsize)
+	}
+
+	return 0
+}
+
+func TestHashReader(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		size := streamSize(h)
+
+		for name, r := range map[string]io.Reader{
+			"bytes":   bytes.NewReader(streamData),
+			"onebyte": iotest.OneByteReader(bytes.NewReader(streamData[:1000])),
+			"dataerr": iotest.DataErrReader(bytes.NewReader(streamData)),
+			"half":    iotest.HalfReader(bytes.NewReader(streamData)),
+		} {
+			data := streamData
+			if name == "onebyte" {
+				data = streamData[:1000]
+			}
+
+			digest, n, err := h.HashID.HashReader(r, size)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+
+			if n != int64(len(data)) {
+				t.Fatalf("%s: expected %d bytes, got %d", name, len(data), n)
+			}
+
+			if want := h.HashID.New().Hash(size, data); !bytes.Equal(digest, want) {
+				t.Fatalf("%s: expected %x, got %x", name, want, digest)
+			}
+		}
+
+		digest, n, err := h.HashID.HashReader(bytes.NewReader(nil), size)
+		if err != nil || n != 0 || !bytes.Equal(digest, h.HashID.New().Hash(size)) {
+			t.Fatalf("unexpected hash of the empty stream: %x, %d, %v", digest, n, err)
+		}
+	})
+}
+
+func TestHashReaderErrors(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType == hash.ExtendableOutputFunction {
+			if _, _, err := h.HashID.HashReader(bytes.NewReader(streamData), uint(h.outputsize-1)); !errors.Is(
+				err,
+				hash.ErrSmallOutputSize,
+			) {
+				t.Fatalf("expected error %q, got %v", hash.ErrSmallOutputSize, err)
+			}
+		}
+
+		r := io.MultiReader(bytes.NewReader(streamData[:100]), iotest.ErrReader(errRead))
+		if _, n, err := h.HashID.HashReader(r, streamSize(h)); !errors.Is(err, errRead) || n != 100 {
+			t.Fatalf("expected error %q after 100 bytes, got %v after %d", errRead, err, n)
+		}
+	})
+
+	if _, _, err := hash.Hash(0).HashReader(bytes.NewReader(streamData), 32); !errors.Is(err, hash.ErrUnavailable) {
+		t.Fatalf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if _, _, err := hash.Hash(0).HashFile("nonexistent", 32); !errors.Is(err, hash.ErrUnavailable) {
+		t.Fatalf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
+
+func TestHashReaderContext(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, n, err := h.HashID.HashReaderContext(ctx, bytes.NewReader(streamData), streamSize(h)); !errors.Is(
+			err,
+			context.Canceled,
+		) || n != 0 {
+			t.Fatalf("expected error %q before reading, got %v after %d bytes", context.Canceled, err, n)
+		}
+
+		// Cancellation is observed between chunks.
+		ctx, cancel = context.WithCancel(context.Background())
+		r := &cancelingReader{Reader: bytes.NewReader(streamData), cancel: cancel}
+
+		_, n, err := h.HashID.HashReaderContext(ctx, r, streamSize(h))
+		if !errors.Is(err, context.Canceled) || n == 0 || n >= int64(len(streamData)) {
+			t.Fatalf("expected error %q after the first chunk, got %v after %d bytes", context.Canceled, err, n)
+		}
+	})
+}
+
+func TestHashFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, streamData, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	testAll(t, func(h *testHash) {
+		size := streamSize(h)
+		expected := h.HashID.New().Hash(size, streamData)
+
+		digest, n, err := h.HashID.HashFile(path, size)
+		if err != nil || n != int64(len(streamData)) || !bytes.Equal(digest, expected) {
+			t.Fatalf("expected %x of %d bytes, got %x of %d: %v", expected, len(streamData), digest, n, err)
+		}
+
+		if _, _, err = h.HashID.HashFile(filepath.Join(t.TempDir(), "nonexistent"), size); !errors.Is(
+			err,
+			fs.ErrNotExist,
+		) {
+			t.Fatalf("expected error %q, got %v", fs.ErrNotExist, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, _, err = h.HashID.HashFileContext(ctx, path, size); !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected error %q, got %v", context.Canceled, err)
+		}
+	})
+}
+
+func BenchmarkHashReader(b *testing.B) {
+	benchmarkAll(b, func(b *testing.B, h *testHash) {
+		b.SetBytes(int64(len(streamData)))
+
+		for range b.N {
+			_, _, _ = h.HashID.HashReader(bytes.NewReader(streamData), streamSize(h))
+		}
+	})
+}
@@ -10,12 +10,19 @@ package tests_test
 
 import (
 	"crypto"
+	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	stdhash "hash"
+	"path/filepath"
 	"testing"
 
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/blake2s"
+
 	"github.com/bytemare/hash"
 )
 
@@ -25,6 +32,7 @@ const (
 	shake256 = "SHAKE256"
 	blake2xb = "BLAKE2XB"
 	blake2xs = "BLAKE2XS"
+	blake3   = "BLAKE3"
 )
 
 type testHash struct {
@@ -38,15 +46,44 @@ type testHash struct {
 }
 
 const (
+	blockSHA3224 = 1152 / 8
 	blockSHA3256 = 1088 / 8
 	blockSHA3384 = 832 / 8
 	blockSHA3512 = 576 / 8
 )
 
-var testHashes = []*testHash{
+var builtinHashes = []*testHash{
+	{hash.FixedOutputLength, crypto.SHA224.String(), crypto.SHA224, sha256.BlockSize, sha256.Size224, 112, hash.SHA224},
 	{hash.FixedOutputLength, crypto.SHA256.String(), crypto.SHA256, sha256.BlockSize, sha256.Size, 128, hash.SHA256},
 	{hash.FixedOutputLength, crypto.SHA384.String(), crypto.SHA384, sha512.BlockSize, sha512.Size384, 192, hash.SHA384},
 	{hash.FixedOutputLength, crypto.SHA512.String(), crypto.SHA512, sha512.BlockSize, sha512.Size, 256, hash.SHA512},
+	{
+		hash.FixedOutputLength,
+		crypto.SHA512_224.String(),
+		crypto.SHA512_224,
+		sha512.BlockSize,
+		sha512.Size224,
+		112,
+		hash.SHA512_224,
+	},
+	{
+		hash.FixedOutputLength,
+		crypto.SHA512_256.String(),
+		crypto.SHA512_256,
+		sha512.BlockSize,
+		sha512.Size256,
+		128,
+		hash.SHA512_256,
+	},
+	{
+		hash.FixedOutputLength,
+		crypto.SHA3_224.String(),
+		crypto.SHA3_224,
+		blockSHA3224,
+		crypto.SHA3_224.Size(),
+		112,
+		hash.SHA3_224,
+	},
 	{
 		hash.FixedOutputLength,
 		crypto.SHA3_256.String(),
@@ -74,10 +111,65 @@ var testHashes = []*testHash{
 		256,
 		hash.SHA3_512,
 	},
+	{
+		hash.FixedOutputLength,
+		crypto.BLAKE2b_256.String(),
+		crypto.BLAKE2b_256,
+		blake2b.BlockSize,
+		blake2b.Size256,
+		128,
+		hash.BLAKE2B_256,
+	},
+	{
+		hash.FixedOutputLength,
+		crypto.BLAKE2b_384.String(),
+		crypto.BLAKE2b_384,
+		blake2b.BlockSize,
+		blake2b.Size384,
+		192,
+		hash.BLAKE2B_384,
+	},
+	{
+		hash.FixedOutputLength,
+		crypto.BLAKE2b_512.String(),
+		crypto.BLAKE2b_512,
+		blake2b.BlockSize,
+		blake2b.Size,
+		256,
+		hash.BLAKE2B_512,
+	},
+	{
+		hash.FixedOutputLength,
+		crypto.BLAKE2s_256.String(),
+		crypto.BLAKE2s_256,
+		blake2s.BlockSize,
+		blake2s.Size,
+		128,
+		hash.BLAKE2S_256,
+	},
 	{hash.ExtendableOutputFunction, shake128, crypto.Hash(0), 168, 32, 128, hash.SHAKE128},
 	{hash.ExtendableOutputFunction, shake256, crypto.Hash(0), 136, 32, 224, hash.SHAKE256},
 	{hash.ExtendableOutputFunction, blake2xb, crypto.Hash(0), 0, 32, 128, hash.BLAKE2XB},
 	{hash.ExtendableOutputFunction, blake2xs, crypto.Hash(0), 0, 32, 128, hash.BLAKE2XS},
+	{hash.ExtendableOutputFunction, blake3, crypto.Hash(0), 64, 32, 128, hash.BLAKE3},
+}
+
+// excluded holds the built-in hash functions excluded from the build with build tags.
+var excluded []hash.Hash
+
+// testHashes holds the built-in hash functions that are available, i.e. not excluded with a build tag.
+var testHashes = available(builtinHashes)
+
+func available(hashes []*testHash) []*testHash {
+	a := make([]*testHash, 0, len(hashes))
+
+	for _, h := range hashes {
+		if h.HashID.Available() {
+			a = append(a, h)
+		}
+	}
+
+	return a
 }
 
 func testAll(t *testing.T, f func(*testHash)) {
@@ -137,3 +229,67 @@ func expectPanic(expectedError error, f func()) (bool, error) {
 
 	return true, nil
 }
+
+func decodeHex(t *testing.T, s string) []byte {
+	t.Helper()
+
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return b
+}
+
+// skipUnavailable skips the test if one of the hash functions was excluded from the build with a build tag.
+func skipUnavailable(t *testing.T, hashes ...hash.Hash) {
+	t.Helper()
+
+	for _, h := range hashes {
+		if !h.Available() {
+			t.Skipf("hash function %d is excluded from the build", h)
+		}
+	}
+}
+
+// vectorFiles returns the test vector files matching pattern in the dir directory of testdata, holding official
+// vectors, and in its counterpart in testdata/generated, holding the ones generated for this package.
+func vectorFiles(t *testing.T, dir, pattern string) []string {
+	t.Helper()
+
+	var files []string
+
+	for _, d := range []string{filepath.Join("testdata", dir), filepath.Join("testdata", "generated", dir)} {
+		matches, err := filepath.Glob(filepath.Join(d, pattern))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	if len(files) == 0 {
+		t.Fatalf("no test vectors found in %s", dir)
+	}
+
+	return files
+}
+
+// vectorName returns the name of the test vector file relative to testdata, to name subtests.
+func vectorName(file string) string {
+	name, err := filepath.Rel("testdata", file)
+	if err != nil {
+		return file
+	}
+
+	return filepath.ToSlash(name)
+}
+
+// referenceHMAC returns the HMAC of message with key over h computed by crypto/hmac, which, unlike Hmac, accepts keys
+// longer than the output size.
+func referenceHMAC(h hash.Hash, message, key []byte) []byte {
+	mac := hmac.New(func() stdhash.Hash { return h.New() }, key)
+	_, _ = mac.Write(message)
+
+	return mac.Sum(nil)
+}
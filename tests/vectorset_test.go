@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+// vectorSetHashes maps the algorithm names, as spelled in the vector sets, to the hash functions.
+var vectorSetHashes = map[string]hash.Hash{
+	"SHA2-224":     hash.SHA224,
+	"SHA2-256":     hash.SHA256,
+	"SHA2-384":     hash.SHA384,
+	"SHA2-512":     hash.SHA512,
+	"SHA2-512/224": hash.SHA512_224,
+	"SHA2-512/256": hash.SHA512_256,
+	"SHA3-224":     hash.SHA3_224,
+	"SHA3-256":     hash.SHA3_256,
+	"SHA3-384":     hash.SHA3_384,
+	"SHA3-512":     hash.SHA3_512,
+	"SHAKE-128":    hash.SHAKE128,
+	"SHAKE-256":    hash.SHAKE256,
+}
+
+// hexBytes decodes hex encoded JSON strings.
+type hexBytes []byte
+
+func (b *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := hex.DecodeString(s)
+	*b = decoded
+
+	return err
+}
+
+// vectorSet is a vector set in the JSON layout of the NIST ACVP protocol, with the expected results merged into the
+// test cases, covering the hash, HMAC, and KDA HKDF test types. Lengths are in bits.
+type vectorSet struct {
+	Algorithm  string `json:"algorithm"`
+	Mode       string `json:"mode"`
+	TestGroups []struct {
+		TestType   string `json:"testType"`
+		MctVersion string `json:"mctVersion"`
+		HmacAlg    string `json:"hmacAlg"`
+		Tests      []struct {
+			Msg          hexBytes `json:"msg"`
+			MD           hexBytes `json:"md"`
+			Key          hexBytes `json:"key"`
+			Mac          hexBytes `json:"mac"`
+			FixedInfo    hexBytes `json:"fixedInfo"`
+			Dkm          hexBytes `json:"dkm"`
+			ResultsArray []struct {
+				MD hexBytes `json:"md"`
+			} `json:"resultsArray"`
+			KdfParameter struct {
+				Salt hexBytes `json:"salt"`
+				Z    hexBytes `json:"z"`
+				L    int      `json:"l"`
+			} `json:"kdfParameter"`
+			TcID   int `json:"tcId"`
+			Len    int `json:"len"`
+			OutLen int `json:"outLen"`
+			KeyLen int `json:"keyLen"`
+			MacLen int `json:"macLen"`
+		} `json:"tests"`
+	} `json:"testGroups"`
+}
+
+// loadVectorSet loads a vector set, either as a plain object or in the array form of the ACVP protocol, where the
+// vector set follows the version object.
+func loadVectorSet(t *testing.T, path string) *vectorSet {
+	t.Helper()
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []json.RawMessage
+	if err = json.Unmarshal(contents, &messages); err == nil && len(messages) != 0 {
+		contents = messages[len(messages)-1]
+	}
+
+	var v vectorSet
+	if err = json.Unmarshal(contents, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	return &v
+}
+
+// TestVectorSets runs the generated vector sets. They only borrow the ACVP layout, and are computed with Python's
+// hashlib and hmac modules, so they cross-check the functions against another implementation rather than against ACVP.
+func TestVectorSets(t *testing.T) {
+	for _, file := range vectorFiles(t, "vectorsets", "*.json") {
+		t.Run(vectorName(file), func(t *testing.T) {
+			v := loadVectorSet(t, file)
+
+			switch {
+			case v.Algorithm == "KDA" && v.Mode == "HKDF":
+				testVectorSetHKDF(t, v)
+			case strings.HasPrefix(v.Algorithm, "HMAC-"):
+				testVectorSetHMAC(t, v)
+			default:
+				testVectorSetHash(t, v)
+			}
+		})
+	}
+}
+
+func vectorSetHash(t *testing.T, algorithm string) hash.Hash {
+	t.Helper()
+
+	h, ok := vectorSetHashes[algorithm]
+	if !ok {
+		t.Skipf("unsupported algorithm %q", algorithm)
+	}
+
+	skipUnavailable(t, h)
+
+	return h
+}
+
+// testVectorSetHash runs the algorithm functional tests (AFT), the standard Monte Carlo tests (MCT), and the variable
+// output tests (VOT) of hash functions.
+func testVectorSetHash(t *testing.T, v *vectorSet) {
+	h := vectorSetHash(t, v.Algorithm)
+
+	for _, group := range v.TestGroups {
+		for _, test := range group.Tests {
+			if test.Len%8 != 0 {
+				continue // Bit-oriented messages are not supported.
+			}
+
+			msg := test.Msg[:test.Len/8]
+
+			switch group.TestType {
+			case "AFT", "VOT":
+				if test.OutLen != 0 && test.OutLen != 8*len(test.MD) {
+					t.Fatalf("tcId %d: inconsistent output length", test.TcID)
+				}
+
+				if digest := nistDigest(h, msg, len(test.MD)); !bytes.Equal(digest, test.MD) {
+					t.Fatalf("tcId %d: expected %x, got %x", test.TcID, test.MD, digest)
+				}
+			case "MCT":
+				if group.MctVersion != "standard" || h.Type() != hash.FixedOutputLength {
+					t.Skipf("unsupported Monte Carlo test %q for %s", group.MctVersion, h)
+				}
+
+				checkpoints := monteCarlo(h, msg)
+				if len(test.ResultsArray) != mctCheckpoints {
+					t.Fatalf("tcId %d: expected %d results, got %d", test.TcID, mctCheckpoints, len(test.ResultsArray))
+				}
+
+				for i, result := range test.ResultsArray {
+					if !bytes.Equal(checkpoints[i], result.MD) {
+						t.Fatalf("tcId %d, %d: expected %x, got %x", test.TcID, i, result.MD, checkpoints[i])
+					}
+				}
+			default:
+				t.Fatalf("unsupported test type %q", group.TestType)
+			}
+		}
+	}
+}
+
+// testVectorSetHMAC runs HMAC tests. Keys longer than the output size are rejected by Hmac, which is checked along the
+// tag computed by crypto/hmac.
+func testVectorSetHMAC(t *testing.T, v *vectorSet) {
+	h := vectorSetHash(t, strings.TrimPrefix(v.Algorithm, "HMAC-"))
+
+	for _, group := range v.TestGroups {
+		for _, test := range group.Tests {
+			if test.KeyLen != 8*len(test.Key) || test.MacLen != 8*len(test.Mac) {
+				t.Fatalf("tcId %d: inconsistent lengths", test.TcID)
+			}
+
+			mac, err := h.GetHashFunction().HmacE(test.Msg, test.Key)
+			if len(test.Key) > h.Size() {
+				if !errors.Is(err, hash.ErrHmacKeySize) {
+					t.Fatalf("tcId %d: expected error %q, got %v", test.TcID, hash.ErrHmacKeySize, err)
+				}
+
+				mac, err = referenceHMAC(h, test.Msg, test.Key), nil
+			}
+
+			if err != nil || !bytes.Equal(mac[:len(test.Mac)], test.Mac) {
+				t.Fatalf("tcId %d: expected %x, got %x: %v", test.TcID, test.Mac, mac, err)
+			}
+		}
+	}
+}
+
+// testVectorSetHKDF runs KDA HKDF tests, in which the fixed info is given as assembled.
+func testVectorSetHKDF(t *testing.T, v *vectorSet) {
+	for _, group := range v.TestGroups {
+		h := vectorSetHash(t, group.HmacAlg)
+
+		for _, test := range group.Tests {
+			p := test.KdfParameter
+			if p.L != 8*len(test.Dkm) {
+				t.Fatalf("tcId %d: inconsistent lengths", test.TcID)
+			}
+
+			dkm, err := h.GetHashFunction().HKDFE(p.Z, p.Salt, test.FixedInfo, len(test.Dkm))
+			if err != nil || !bytes.Equal(dkm, test.Dkm) {
+				t.Fatalf("tcId %d: expected %x, got %x: %v", test.TcID, test.Dkm, dkm, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+func flipped(b []byte) []byte {
+	f := append([]byte{}, b...)
+	f[len(f)-1] ^= 1
+
+	return f
+}
+
+func TestMinTagLength(t *testing.T) {
+	for id, expected := range map[hash.Hash]int{
+		hash.SHA224:   14,
+		hash.SHA256:   16,
+		hash.SHA512:   32,
+		hash.SHAKE128: 16,
+		hash.BLAKE3:   16,
+	} {
+		if !id.Available() {
+			continue
+		}
+
+		if length := id.MinTagLength(); length != expected {
+			t.Errorf("%s: expected %d, got %d", id, expected, length)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		digest := h.HashID.Hash(testData.message, testData.secret)
+
+		if !h.HashID.Verify(digest, testData.message, testData.secret) {
+			t.Fatal("expected valid digest")
+		}
+
+		if valid, err := h.HashID.VerifyE(digest, testData.message, testData.secret); err != nil || !valid {
+			t.Fatalf("expected valid digest: %v", err)
+		}
+
+		for _, invalid := range [][]byte{nil, digest[:len(digest)-1], flipped(digest)} {
+			if h.HashID.Verify(invalid, testData.message, testData.secret) {
+				t.Fatalf("unexpected valid digest %x", invalid)
+			}
+		}
+
+		long := append(append([]byte{}, digest...), 0)
+		if h.HashType == hash.ExtendableOutputFunction {
+			long = h.HashID.GetXOF().Hash(uint(len(digest)+1), testData.message, testData.secret)
+		}
+
+		if h.HashID.Verify(long, testData.message, testData.secret) != (h.HashType == hash.ExtendableOutputFunction) {
+			t.Fatalf("unexpected verification of a long digest")
+		}
+
+		for _, length := range []int{h.HashID.MinTagLength(), len(digest)} {
+			valid, err := h.HashID.VerifyTruncated(digest[:length], testData.message, testData.secret)
+			if err != nil || !valid {
+				t.Fatalf("%d: expected valid truncated digest: %v", length, err)
+			}
+
+			valid, err = h.HashID.VerifyTruncated(flipped(digest[:length]), testData.message, testData.secret)
+			if err != nil || valid {
+				t.Fatalf("%d: unexpected valid truncated digest: %v", length, err)
+			}
+		}
+
+		if _, err := h.HashID.VerifyTruncated(digest[:h.HashID.MinTagLength()-1], testData.message); !errors.Is(
+			err,
+			hash.ErrTagLength,
+		) {
+			t.Fatalf("expected error %q, got %v", hash.ErrTagLength, err)
+		}
+
+		valid, err := h.HashID.VerifyTruncated(long, testData.message, testData.secret)
+		if h.HashType == hash.FixedOutputLength && !errors.Is(err, hash.ErrTagLength) {
+			t.Fatalf("expected error %q, got %v", hash.ErrTagLength, err)
+		}
+
+		if h.HashType == hash.ExtendableOutputFunction && (err != nil || !valid) {
+			t.Fatalf("expected valid long digest: %v", err)
+		}
+	})
+
+	if hash.Hash(0).Verify(nil) {
+		t.Error("expected verification to fail for an unavailable hash function")
+	}
+
+	if _, err := hash.Hash(0).VerifyE(nil); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+
+	if _, err := hash.Hash(0).VerifyTruncated(nil); !errors.Is(err, hash.ErrUnavailable) {
+		t.Errorf("expected error %q, got %v", hash.ErrUnavailable, err)
+	}
+}
+
+func TestHmacVerify(t *testing.T) {
+	testAll(t, func(h *testHash) {
+		if h.HashType != hash.FixedOutputLength {
+			return
+		}
+
+		hasher := h.HashID.GetHashFunction()
+		key := testData.secret
+		tag := hasher.Hmac(testData.message, key)
+
+		if !hasher.HmacVerify(testData.message, key, tag) {
+			t.Fatal("expected valid tag")
+		}
+
+		if valid, err := hasher.HmacVerifyE(testData.message, key, tag); err != nil || !valid {
+			t.Fatalf("expected valid tag: %v", err)
+		}
+
+		for _, invalid := range [][]byte{nil, tag[:len(tag)-1], flipped(tag)} {
+			if hasher.HmacVerify(testData.message, key, invalid) {
+				t.Fatalf("unexpected valid tag %x", invalid)
+			}
+		}
+
+		if hasher.HmacVerify(testData.info, key, tag) {
+			t.Fatal("unexpected valid tag for another message")
+		}
+
+		for _, length := range []int{h.HashID.MinTagLength(), len(tag)} {
+			valid, err := hasher.HmacVerifyTruncated(testData.message, key, tag[:length])
+			if err != nil || !valid {
+				t.Fatalf("%d: expected valid truncated tag: %v", length, err)
+			}
+
+			valid, err = hasher.HmacVerifyTruncated(testData.message, key, flipped(tag[:length]))
+			if err != nil || valid {
+				t.Fatalf("%d: unexpected valid truncated tag: %v", length, err)
+			}
+		}
+
+		for _, invalid := range [][]byte{tag[:h.HashID.MinTagLength()-1], append(tag, 0)} {
+			if _, err := hasher.HmacVerifyTruncated(testData.message, key, invalid); !errors.Is(
+				err,
+				hash.ErrTagLength,
+			) {
+				t.Fatalf("%d: expected error %q, got %v", len(invalid), hash.ErrTagLength, err)
+			}
+		}
+
+		longKey := make([]byte, h.HashID.Size()+1)
+
+		if _, err := hasher.HmacVerifyTruncated(testData.message, longKey, tag); !errors.Is(
+			err,
+			hash.ErrHmacKeySize,
+		) {
+			t.Fatalf("expected error %q, got %v", hash.ErrHmacKeySize, err)
+		}
+
+		valid, err := hasher.HmacVerifyE(testData.message, longKey, tag)
+		if !errors.Is(err, hash.ErrHmacKeySize) || valid {
+			t.Fatalf("expected error %q, got %v", hash.ErrHmacKeySize, err)
+		}
+
+		if hasher.HmacVerify(testData.message, longKey, tag) {
+			t.Fatal("unexpected valid tag for an oversized key")
+		}
+	})
+}
@@ -0,0 +1,187 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package tests_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bytemare/hash"
+)
+
+const (
+	wycheproofValid   = "valid"
+	wycheproofInvalid = "invalid"
+)
+
+// wycheproofFile is a Wycheproof test vector file of the MacTest or HkdfTest types.
+type wycheproofFile struct {
+	Algorithm  string `json:"algorithm"`
+	Schema     string `json:"schema"`
+	TestGroups []struct {
+		Type    string           `json:"type"`
+		Tests   []wycheproofTest `json:"tests"`
+		TagSize int              `json:"tagSize"`
+	} `json:"testGroups"`
+	NumberOfTests int `json:"numberOfTests"`
+}
+
+type wycheproofTest struct {
+	Comment string   `json:"comment"`
+	Result  string   `json:"result"`
+	Flags   []string `json:"flags"`
+	Key     hexBytes `json:"key"`
+	Msg     hexBytes `json:"msg"`
+	Tag     hexBytes `json:"tag"`
+	Ikm     hexBytes `json:"ikm"`
+	Salt    hexBytes `json:"salt"`
+	Info    hexBytes `json:"info"`
+	Okm     hexBytes `json:"okm"`
+	TcID    int      `json:"tcId"`
+	Size    int      `json:"size"`
+}
+
+// loadWycheproof loads the Wycheproof file, and returns it with the hash function designated by its algorithm name,
+// after the prefix.
+func loadWycheproof(t *testing.T, path, prefix string) (*wycheproofFile, *hash.Fixed) {
+	t.Helper()
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var f wycheproofFile
+	if err = json.Unmarshal(contents, &f); err != nil {
+		t.Fatal(err)
+	}
+
+	name, ok := strings.CutPrefix(f.Algorithm, prefix)
+	if !ok {
+		t.Fatalf("unexpected algorithm %q", f.Algorithm)
+	}
+
+	h, err := hash.Parse(name)
+	if err != nil || h.Type() != hash.FixedOutputLength {
+		t.Skipf("unsupported hash function %q", name)
+	}
+
+	count := 0
+	for _, group := range f.TestGroups {
+		count += len(group.Tests)
+	}
+
+	if count != f.NumberOfTests {
+		t.Fatalf("expected %d tests, found %d", f.NumberOfTests, count)
+	}
+
+	return &f, h.GetHashFunction()
+}
+
+// TestWycheproofHMAC runs the HMAC suites. Hmac rejects keys longer than the hash output size, so such vectors are
+// expected to fail with ErrHmacKeySize, and their tags are checked against crypto/hmac instead. The tag size is a
+// parameter of each group, such that tags of another length are invalid. Invalid tags must be rejected, either as not
+// matching or with ErrTagLength.
+func TestWycheproofHMAC(t *testing.T) {
+	for _, file := range vectorFiles(t, "wycheproof", "hmac_*_test.json") {
+		t.Run(vectorName(file), func(t *testing.T) {
+			f, h := loadWycheproof(t, file, "HMAC")
+
+			for _, group := range f.TestGroups {
+				for _, test := range group.Tests {
+					testWycheproofHMAC(t, h, group.TagSize/8, &test)
+				}
+			}
+		})
+	}
+}
+
+func testWycheproofHMAC(t *testing.T, h *hash.Fixed, tagSize int, test *wycheproofTest) {
+	t.Helper()
+
+	valid, err := h.HmacVerifyTruncated(test.Msg, test.Key, test.Tag)
+	valid = valid && len(test.Tag) == tagSize
+
+	switch {
+	case len(test.Key) > h.Size():
+		if _, err = h.HmacE(test.Msg, test.Key); !errors.Is(err, hash.ErrHmacKeySize) {
+			t.Fatalf("tcId %d: expected error %q, got %v", test.TcID, hash.ErrHmacKeySize, err)
+		}
+
+		mac := referenceHMAC(h.Algorithm(), test.Msg, test.Key)
+		matches := len(test.Tag) == tagSize && bytes.Equal(mac[:min(len(test.Tag), len(mac))], test.Tag)
+
+		if matches != (test.Result == wycheproofValid) {
+			t.Fatalf("tcId %d (%s): unexpected %s tag %x, crypto/hmac computes %x", test.TcID, test.Comment,
+				test.Result, test.Tag, mac)
+		}
+	case test.Result == wycheproofValid:
+		if err != nil || !valid {
+			t.Fatalf("tcId %d (%s): expected valid tag: %v", test.TcID, test.Comment, err)
+		}
+
+		if mac := h.Hmac(test.Msg, test.Key); !bytes.Equal(mac[:len(test.Tag)], test.Tag) {
+			t.Fatalf("tcId %d: expected %x, got %x", test.TcID, test.Tag, mac)
+		}
+	case test.Result == wycheproofInvalid:
+		if valid || (err != nil && !errors.Is(err, hash.ErrTagLength)) {
+			t.Fatalf("tcId %d (%s): expected invalid tag, got %v: %v", test.TcID, test.Comment, valid, err)
+		}
+	default:
+		t.Fatalf("tcId %d: unexpected result %q", test.TcID, test.Result)
+	}
+}
+
+// TestWycheproofHKDF runs the HKDF suites, and the RFC 5869 test cases in the same format, with HKDF and with
+// HKDFExtract followed by HKDFExpand. Output sizes larger than 255 times the hash output size must fail with
+// ErrHKDFLength.
+func TestWycheproofHKDF(t *testing.T) {
+	files := append(vectorFiles(t, "wycheproof", "hkdf_*_test.json"), vectorFiles(t, "rfc5869", "hkdf_*_test.json")...)
+
+	for _, file := range files {
+		t.Run(vectorName(file), func(t *testing.T) {
+			f, h := loadWycheproof(t, file, "HKDF-")
+
+			for _, group := range f.TestGroups {
+				for _, test := range group.Tests {
+					testWycheproofHKDF(t, h, &test)
+				}
+			}
+		})
+	}
+}
+
+func testWycheproofHKDF(t *testing.T, h *hash.Fixed, test *wycheproofTest) {
+	t.Helper()
+
+	okm, err := h.HKDFE(test.Ikm, test.Salt, test.Info, test.Size)
+	expanded, expandErr := h.HKDFExpandE(h.HKDFExtract(test.Ikm, test.Salt), test.Info, test.Size)
+
+	switch test.Result {
+	case wycheproofValid:
+		if err != nil || !bytes.Equal(okm, test.Okm) {
+			t.Fatalf("tcId %d (%s): expected %x, got %x: %v", test.TcID, test.Comment, test.Okm, okm, err)
+		}
+
+		if expandErr != nil || !bytes.Equal(expanded, test.Okm) {
+			t.Fatalf("tcId %d (%s): expected %x, got %x: %v", test.TcID, test.Comment, test.Okm, expanded, expandErr)
+		}
+	case wycheproofInvalid:
+		if !errors.Is(err, hash.ErrHKDFLength) || !errors.Is(expandErr, hash.ErrHKDFLength) {
+			t.Fatalf("tcId %d (%s): expected error %q, got %v and %v", test.TcID, test.Comment, hash.ErrHKDFLength,
+				err, expandErr)
+		}
+	default:
+		t.Fatalf("tcId %d: unexpected result %q", test.TcID, test.Result)
+	}
+}
@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: MIT
+//
+// Copyright (C) 2024 Daniel Bourdrez. All Rights Reserved.
+//
+// This source code is licensed under the MIT license found in the
+// LICENSE file in the root directory of this source tree or at
+// https://spdx.org/licenses/MIT.html
+
+package hash
+
+import (
+	"crypto/subtle"
+	"errors"
+)
+
+// minTruncatedLength is the absolute minimum length in bytes of truncated digests and tags, i.e. 80 bits.
+const minTruncatedLength = 10
+
+// ErrTagLength indicates that a truncated digest or tag is shorter than the minimum length, or longer than the output
+// size of a fixed output length function.
+var ErrTagLength = errors.New("invalid truncated digest or tag length")
+
+// MinTagLength returns the minimum length in bytes accepted for truncated digests and tags, which is, as recommended
+// by RFC 2104, the larger of half the output size and 80 bits.
+func (h Hash) MinTagLength() int {
+	return max(h.Size()/2, minTruncatedLength)
+}
+
+// checkTruncated returns ErrTagLength if length is not an acceptable truncated length for h.
+func (h Hash) checkTruncated(length int) error {
+	if length < h.MinTagLength() || (h.Type() == FixedOutputLength && length > h.Size()) {
+		return ErrTagLength
+	}
+
+	return nil
+}
+
+// digest returns the first length bytes of the hash of the concatenated input, with length at most the output size
+// for fixed output length functions.
+func (h Hash) digest(length int, input ...[]byte) ([]byte, error) {
+	hasher, err := h.NewE()
+	if err != nil {
+		return nil, err
+	}
+
+	return hasher.Hash(uint(max(length, h.Size())), input...)[:length], nil
+}
+
+// Verify reports whether expected is the hash of the concatenated input, comparing in constant time. For extendable
+// output functions, expected can be longer than the output size. It returns false if the hash function is not
+// available, use VerifyE to tell this case apart.
+func (h Hash) Verify(expected []byte, input ...[]byte) bool {
+	valid, _ := h.VerifyE(expected, input...)
+	return valid
+}
+
+// VerifyE reports whether expected is the hash of the concatenated input, comparing in constant time. For extendable
+// output functions, expected can be longer than the output size. It returns ErrUnavailable if the hash function is
+// not available.
+func (h Hash) VerifyE(expected []byte, input ...[]byte) (bool, error) {
+	if !h.Available() {
+		return false, ErrUnavailable
+	}
+
+	if len(expected) < h.Size() || (h.Type() == FixedOutputLength && len(expected) != h.Size()) {
+		return false, nil
+	}
+
+	digest, err := h.digest(len(expected), input...)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(digest, expected) == 1, nil
+}
+
+// VerifyTruncated reports whether expected is the, possibly truncated, hash of the concatenated input, comparing in
+// constant time. It returns ErrTagLength if expected is shorter than MinTagLength or longer than the output size of a
+// fixed output length function, and ErrUnavailable if the hash function is not available.
+func (h Hash) VerifyTruncated(expected []byte, input ...[]byte) (bool, error) {
+	if !h.Available() {
+		return false, ErrUnavailable
+	}
+
+	if err := h.checkTruncated(len(expected)); err != nil {
+		return false, err
+	}
+
+	digest, err := h.digest(len(expected), input...)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(digest, expected) == 1, nil
+}
+
+// HmacVerify reports whether tag is the HMAC of message with key, comparing in constant time. It returns false if the
+// key is longer than the hash output size, use HmacVerifyE to tell this case apart.
+func (h *Fixed) HmacVerify(message, key, tag []byte) bool {
+	valid, _ := h.HmacVerifyE(message, key, tag)
+	return valid
+}
+
+// HmacVerifyE reports whether tag is the HMAC of message with key, comparing in constant time. It returns
+// ErrHmacKeySize if the key is longer than the hash output size.
+func (h *Fixed) HmacVerifyE(message, key, tag []byte) (bool, error) {
+	mac, err := h.HmacE(message, key)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(mac, tag) == 1, nil
+}
+
+// HmacVerifyTruncated reports whether tag is the, possibly truncated, HMAC of message with key, comparing in constant
+// time. It returns ErrTagLength if tag is shorter than MinTagLength or longer than the output size, and ErrHmacKeySize
+// if the key is longer than the hash output size.
+func (h *Fixed) HmacVerifyTruncated(message, key, tag []byte) (bool, error) {
+	if err := h.id.checkTruncated(len(tag)); err != nil {
+		return false, err
+	}
+
+	mac, err := h.HmacE(message, key)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(mac[:len(tag)], tag) == 1, nil
+}